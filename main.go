@@ -1,12 +1,24 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/log"
 
+	"github.com/phil/docker-manager/pkg/batch"
+	"github.com/phil/docker-manager/pkg/browser"
 	"github.com/phil/docker-manager/pkg/config"
 	"github.com/phil/docker-manager/pkg/discovery"
 	"github.com/phil/docker-manager/pkg/docker"
@@ -23,10 +35,18 @@ func main() {
 	if err := config.EnsureDefaultConfig(); err != nil {
 		logger.Warn("Impossible de créer le fichier de config par défaut", "error", err)
 	}
+	// Une durée invalide garde sa valeur par défaut dans chaque Manager : signalée une seule fois ici
+	if _, err := docker.ConfiguredTimeouts(); err != nil {
+		logger.Warn("Timeouts de la config ignorés, valeurs par défaut utilisées", "error", err)
+	}
+
+	// Ctrl-C annule l'opération en cours et laisse compose s'arrêter proprement
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	if len(os.Args) < 2 {
 		printHelp()
-		handleStatus()
+		handleStatus(ctx, nil, nil)
 		return
 	}
 
@@ -34,46 +54,123 @@ func main() {
 
 	switch command {
 	case "start":
-		if len(os.Args) < 3 {
-			fmt.Println("usage: docker-manager start <project>")
+		fs := flag.NewFlagSet("start", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		startFlags := addStartFlags(fs)
+		batchOpts := addBatchFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
+
+		names, isBatch, err := batchOpts.targets(args)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if isBatch {
+			if err := handleBatchStart(ctx, names, opts, startFlags, batchOpts.parallelism()); err != nil {
+				exitBatchError(err)
+			}
+			break
+		}
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager start <project|group> [service...] [--all] [--no-build|--pull|--no-cache|--force-recreate]")
 			os.Exit(1)
 		}
-		if err := handleStart(os.Args[2]); err != nil {
+		if err := handleStart(ctx, args[0], args[1:], opts, startFlags); err != nil {
 			logger.Fatal(err)
 		}
 
 	case "stop":
-		if len(os.Args) < 3 {
-			fmt.Println("usage: docker-manager stop <project>")
+		fs := flag.NewFlagSet("stop", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		remove := fs.Bool("rm", false, "Supprime aussi les containers des services arrêtés")
+		keep := fs.Bool("keep", false, "Projet entier : stop sans supprimer les containers (au lieu de down)")
+		batchOpts := addBatchFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
+
+		names, isBatch, err := batchOpts.targets(args)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if isBatch {
+			stopOpts := docker.StopOptions{Remove: !*keep}
+			if err := handleBatchStop(ctx, names, stopOpts, opts, batchOpts.parallelism()); err != nil {
+				exitBatchError(err)
+			}
+			break
+		}
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager stop <project|group> [service...] [--all] [--rm] [--keep]")
 			os.Exit(1)
 		}
-		if err := handleStop(os.Args[2]); err != nil {
+
+		stopOpts := docker.StopOptions{Services: args[1:], Remove: *remove}
+		if len(stopOpts.Services) == 0 {
+			stopOpts.Remove = !*keep
+		}
+		if err := handleStop(ctx, args[0], stopOpts, opts); err != nil {
 			logger.Fatal(err)
 		}
 
 	case "restart":
-		if len(os.Args) < 3 {
-			fmt.Println("usage: docker-manager restart <project> [service]")
-			os.Exit(1)
+		fs := flag.NewFlagSet("restart", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		recreate := fs.Bool("recreate", false, "Recrée les containers (down/up) au lieu d'un simple restart")
+		build := fs.Bool("build", false, "Reconstruit les images avant de recréer (implique --recreate)")
+		wait := addWaitFlags(fs)
+		batchOpts := addBatchFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
+
+		restartOpts := docker.RestartOptions{
+			Recreate: *recreate || *build,
+			Build:    *build,
 		}
-		service := ""
-		if len(os.Args) > 3 {
-			service = os.Args[3]
+
+		names, isBatch, err := batchOpts.targets(args)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if isBatch {
+			if err := handleBatchRestart(ctx, names, restartOpts, wait, opts, batchOpts.parallelism()); err != nil {
+				exitBatchError(err)
+			}
+			break
+		}
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager restart <project|group> [service...] [--all] [--recreate] [--build] [--wait]")
+			os.Exit(1)
 		}
-		if err := handleRestart(os.Args[2], service); err != nil {
+
+		restartOpts.Services = args[1:]
+		if err := handleRestart(ctx, args[0], restartOpts, wait, opts); err != nil {
 			logger.Fatal(err)
 		}
 
 	case "status":
-		// docker-manager status [project]
-		if len(os.Args) > 2 {
+		// docker-manager status [project|group] [--all] [--profile name] [--env-file file]
+		fs := flag.NewFlagSet("status", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		batchOpts := addBatchFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
+
+		names, isBatch, err := batchOpts.targets(args)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if isBatch {
+			// Status global restreint au groupe
+			if err := handleStatus(ctx, names, opts); err != nil {
+				logger.Fatal(err)
+			}
+		} else if len(args) > 0 {
 			// Status détaillé d'un projet
-			if err := handleStatusProject(os.Args[2]); err != nil {
+			if err := handleStatusProject(ctx, args[0], opts); err != nil {
 				logger.Fatal(err)
 			}
 		} else {
 			// Status global
-			if err := handleStatus(); err != nil {
+			if err := handleStatus(ctx, nil, opts); err != nil {
 				logger.Fatal(err)
 			}
 		}
@@ -81,9 +178,9 @@ func main() {
 	case "logs":
 		fs := flag.NewFlagSet("logs", flag.ExitOnError)
 		follow := fs.Bool("f", false, "Suit les logs en temps réel")
-		fs.Parse(os.Args[2:])
+		opts := addComposeFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
 
-		args := fs.Args()
 		if len(args) < 1 {
 			fmt.Println("usage: docker-manager logs <project> [service] [-f]")
 			os.Exit(1)
@@ -94,12 +191,218 @@ func main() {
 			service = args[1]
 		}
 
-		if err := handleLogs(args[0], service, *follow); err != nil {
+		if err := handleLogs(ctx, args[0], service, *follow, opts); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "exec":
+		// docker-manager exec <project> <service> -- <cmd...>
+		fs := flag.NewFlagSet("exec", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		noTTY := fs.Bool("T", false, "Désactive l'allocation d'un pseudo-terminal")
+		user := fs.String("user", "", "Utilisateur dans le container")
+		workdir := fs.String("workdir", "", "Répertoire de travail dans le container")
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 3 {
+			fmt.Println("usage: docker-manager exec <project> <service> -- <cmd...>")
+			os.Exit(1)
+		}
+
+		execOpts := docker.ExecOptions{TTY: !*noTTY && stdinIsTerminal(), User: *user, Workdir: *workdir}
+		if err := handleExec(ctx, args[0], args[1], args[2:], execOpts, opts); err != nil {
+			exitWithError(err)
+		}
+
+	case "shell":
+		// docker-manager shell <project> [service]
+		fs := flag.NewFlagSet("shell", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		shell := fs.String("shell", "", "Shell à ouvrir (par défaut: config, sinon bash, sh, ash)")
+		user := fs.String("user", "", "Utilisateur dans le container")
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager shell <project> [service] [--shell bash] [--user root]")
+			os.Exit(1)
+		}
+		service := ""
+		if len(args) > 1 {
+			service = args[1]
+		}
+
+		execOpts := docker.ExecOptions{TTY: stdinIsTerminal(), User: *user}
+		if err := handleShell(ctx, args[0], service, *shell, execOpts, opts); err != nil {
+			exitWithError(err)
+		}
+
+	case "run":
+		// docker-manager run <project> <task> [-- args...]
+		fs := flag.NewFlagSet("run", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		noTTY := fs.Bool("T", false, "Désactive l'allocation d'un pseudo-terminal")
+		user := fs.String("user", "", "Utilisateur dans le container")
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 2 {
+			fmt.Println("usage: docker-manager run <project> <task> [-- args...]")
+			os.Exit(1)
+		}
+
+		execOpts := docker.ExecOptions{TTY: !*noTTY && stdinIsTerminal(), User: *user}
+		if err := handleRun(ctx, args[0], args[1], args[2:], execOpts, opts); err != nil {
+			exitWithError(err)
+		}
+
+	case "tasks":
+		// docker-manager tasks [project]
+		projectName := ""
+		if len(os.Args) > 2 {
+			projectName = os.Args[2]
+		}
+		if err := handleTasks(projectName); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "stats":
+		// docker-manager stats [project] [-f] [--interval 5s]
+		fs := flag.NewFlagSet("stats", flag.ExitOnError)
+		follow := fs.Bool("f", false, "Rafraîchit les statistiques à chaque intervalle")
+		interval := fs.Duration("interval", 5*time.Second, "Intervalle de rafraîchissement avec -f")
+		args := parseFlags(fs, os.Args[2:])
+
+		projectName := ""
+		if len(args) > 0 {
+			projectName = args[0]
+		}
+		if err := handleStats(ctx, projectName, *follow, *interval); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "du":
+		// docker-manager du
+		if err := handleDu(ctx); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "clean":
+		// docker-manager clean <project> [--level orphans|images|volumes|all] [--include-running] [--dry-run] [--yes]
+		fs := flag.NewFlagSet("clean", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		level := fs.String("level", "orphans", "Niveau: orphans, images, volumes ou all")
+		dryRun := fs.Bool("dry-run", false, "Affiche ce qui serait supprimé sans rien supprimer")
+		includeRunning := fs.Bool("include-running", false, "Arrête et supprime aussi les containers démarrés")
+		yes := fs.Bool("yes", false, "Ne demande pas de confirmation avant de supprimer des volumes ou des containers démarrés")
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager clean <project> [--level orphans|images|volumes|all] [--include-running] [--dry-run] [--yes]")
+			os.Exit(1)
+		}
+		cleanLevel, err := docker.ParseCleanLevel(*level)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if err := handleClean(ctx, args[0], cleanLevel, *includeRunning, *dryRun, *yes, opts); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "backup":
+		// docker-manager backup <project> [volume...]
+		fs := flag.NewFlagSet("backup", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager backup <project> [volume...]")
+			os.Exit(1)
+		}
+		if err := handleBackup(ctx, args[0], args[1:], opts); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "restore":
+		// docker-manager restore <project> <volume> [archive] [--yes]
+		fs := flag.NewFlagSet("restore", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		yes := fs.Bool("yes", false, "Ne demande pas de confirmation avant d'écraser le volume")
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 2 {
+			fmt.Println("usage: docker-manager restore <project> <volume> [archive|date] [--yes]")
+			os.Exit(1)
+		}
+		archive := ""
+		if len(args) > 2 {
+			archive = args[2]
+		}
+		if err := handleRestore(ctx, args[0], args[1], archive, *yes, opts); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "backups":
+		// docker-manager backups [project]
+		projectName := ""
+		if len(os.Args) > 2 {
+			projectName = os.Args[2]
+		}
+		if err := handleBackups(projectName); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "check":
+		// docker-manager check [project] : code de sortie 1 si une sonde échoue
+		projectName := ""
+		if len(os.Args) > 2 {
+			projectName = os.Args[2]
+		}
+		if err := handleCheck(ctx, projectName); err != nil {
+			if errors.Is(err, errCheckFailed) {
+				os.Exit(1)
+			}
+			logger.Fatal(err)
+		}
+
+	case "open":
+		// docker-manager open <project> [service] : ouvre l'URL dans le navigateur
+		fs := flag.NewFlagSet("open", flag.ExitOnError)
+		opts := addComposeFlags(fs)
+		args := parseFlags(fs, os.Args[2:])
+
+		if len(args) < 1 {
+			fmt.Println("usage: docker-manager open <project> [service]")
+			os.Exit(1)
+		}
+		service := ""
+		if len(args) > 1 {
+			service = args[1]
+		}
+		if err := handleOpen(ctx, args[0], service, opts); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "deps":
+		// docker-manager deps [project] : graphe des depends_on entre projets
+		projectName := ""
+		if len(os.Args) > 2 {
+			projectName = os.Args[2]
+		}
+		if err := handleDeps(projectName); err != nil {
+			logger.Fatal(err)
+		}
+
+	case "events":
+		// docker-manager events [project]
+		projectName := ""
+		if len(os.Args) > 2 {
+			projectName = os.Args[2]
+		}
+		if err := handleEvents(ctx, projectName); err != nil {
 			logger.Fatal(err)
 		}
 
 	case "dashboard":
-		if err := handleDashboard(); err != nil {
+		if err := handleDashboard(ctx); err != nil {
 			logger.Fatal(err)
 		}
 
@@ -108,7 +411,7 @@ func main() {
 			fmt.Println("usage: docker-manager daemon <start|stop|status>")
 			os.Exit(1)
 		}
-		if err := handleDaemon(os.Args[2]); err != nil {
+		if err := handleDaemon(ctx, os.Args[2]); err != nil {
 			logger.Fatal(err)
 		}
 
@@ -125,6 +428,265 @@ func main() {
 	}
 }
 
+// stringList est une option répétable qui accepte aussi les listes "a,b"
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// parseFlags accepte les options avant ou après les arguments positionnels
+// (ex: "logs pbwww nginx -f") et retourne les arguments positionnels.
+// Tout ce qui suit "--" est ajouté tel quel, sans interprétation.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var passthrough []string
+	for i, arg := range args {
+		if arg == "--" {
+			passthrough = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return append(positional, passthrough...)
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// stdinIsTerminal indique si l'entrée standard est un terminal (TTY possible)
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// exitWithError quitte avec le code de sortie de la commande exécutée
+// dans le container, ou affiche l'erreur
+func exitWithError(err error) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	logger.Fatal(err)
+}
+
+// errBatchFailed signale qu'au moins un projet du lot a échoué (code de sortie 1)
+var errBatchFailed = errors.New("opérations en échec")
+
+// exitBatchError quitte avec le code 1 si le résumé a déjà été affiché
+func exitBatchError(err error) {
+	if errors.Is(err, errBatchFailed) {
+		os.Exit(1)
+	}
+	logger.Fatal(err)
+}
+
+// batchFlags sont les options des commandes sur plusieurs projets (groupe ou --all)
+type batchFlags struct {
+	all      *bool
+	parallel *int
+}
+
+// addBatchFlags déclare --all et --parallel
+func addBatchFlags(fs *flag.FlagSet) *batchFlags {
+	return &batchFlags{
+		all:      fs.Bool("all", false, "Tous les projets découverts"),
+		parallel: fs.Int("parallel", 0, "Opérations simultanées sur un groupe (défaut: parallelism de projects.yml, sinon 4)"),
+	}
+}
+
+// targets retourne les projets visés par --all ou par un groupe de projects.yml.
+// isBatch est faux quand la commande porte sur un seul projet : un projet
+// est prioritaire sur un groupe du même nom.
+func (f *batchFlags) targets(args []string) (names []string, isBatch bool, err error) {
+	if *f.all {
+		if len(args) > 0 {
+			return nil, false, errors.New("--all ne prend ni projet ni service")
+		}
+		projects, err := discovery.DiscoverInDefaultPath()
+		if err != nil {
+			return nil, false, err
+		}
+		for _, p := range projects {
+			names = append(names, p.Name)
+		}
+		return names, true, nil
+	}
+	if len(args) == 0 {
+		return nil, false, nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	members, ok := cfg.Group(args[0])
+	if !ok {
+		return nil, false, nil
+	}
+	if _, err := findProject(args[0]); err == nil {
+		return nil, false, nil
+	}
+	if len(args) > 1 {
+		return nil, false, fmt.Errorf("le groupe '%s' ne prend pas de service", args[0])
+	}
+	if len(members) == 0 {
+		return nil, false, fmt.Errorf("le groupe '%s' est vide", args[0])
+	}
+	return members, true, nil
+}
+
+// parallelism retourne --parallel, sinon parallelism de projects.yml
+func (f *batchFlags) parallelism() int {
+	if *f.parallel > 0 {
+		return *f.parallel
+	}
+	if cfg, err := config.LoadConfig(); err == nil && cfg.Parallelism > 0 {
+		return cfg.Parallelism
+	}
+	return batch.DefaultParallelism
+}
+
+// startFlags sont les options de mode de démarrage de la commande start
+type startFlags struct {
+	fs            *flag.FlagSet
+	noBuild       *bool
+	build         *bool
+	pull          *bool
+	noCache       *bool
+	forceRecreate *bool
+	ignorePorts   *bool
+	noDeps        *bool
+	wait          *waitFlags
+}
+
+// addStartFlags déclare les options de mode de démarrage
+func addStartFlags(fs *flag.FlagSet) *startFlags {
+	return &startFlags{
+		fs:            fs,
+		noBuild:       fs.Bool("no-build", false, "Démarre sans construire les images"),
+		build:         fs.Bool("build", false, "Force le build même si le projet est configuré en no_build"),
+		pull:          fs.Bool("pull", false, "Met à jour les images avant de démarrer"),
+		noCache:       fs.Bool("no-cache", false, "Construit les images sans cache"),
+		forceRecreate: fs.Bool("force-recreate", false, "Recrée les containers même sans changement"),
+		ignorePorts:   fs.Bool("ignore-port-conflicts", false, "Démarre même si des ports publiés sont déjà utilisés"),
+		noDeps:        fs.Bool("no-deps", false, "Ne démarre pas les projets dont celui-ci dépend (depends_on)"),
+		wait:          addWaitFlags(fs),
+	}
+}
+
+// apply complète le mode par défaut du projet avec les options passées
+func (f *startFlags) apply(base docker.StartOptions, services []string) docker.StartOptions {
+	set := make(map[string]bool)
+	f.fs.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+
+	if set["no-build"] {
+		base.NoBuild = *f.noBuild
+	}
+	if set["build"] && *f.build {
+		base.NoBuild = false
+	}
+	if set["pull"] {
+		base.Pull = *f.pull
+	}
+	if set["no-cache"] {
+		base.NoCache = *f.noCache
+	}
+	if set["force-recreate"] {
+		base.ForceRecreate = *f.forceRecreate
+	}
+	if set["ignore-port-conflicts"] {
+		base.IgnorePortConflicts = *f.ignorePorts
+	}
+	base.Wait, base.WaitTimeout = f.wait.apply(base.Wait, base.WaitTimeout)
+	if len(services) > 0 {
+		base.Services = services
+	}
+	return base
+}
+
+// waitFlags sont les options d'attente de la santé des services (start, restart)
+type waitFlags struct {
+	fs      *flag.FlagSet
+	wait    *bool
+	timeout *time.Duration
+}
+
+// addWaitFlags déclare --wait et --wait-timeout
+func addWaitFlags(fs *flag.FlagSet) *waitFlags {
+	return &waitFlags{
+		fs:      fs,
+		wait:    fs.Bool("wait", false, "Attend que les services soient opérationnels (healthcheck)"),
+		timeout: fs.Duration("wait-timeout", docker.DefaultWaitTimeout, "Durée maximale d'attente (implique --wait)"),
+	}
+}
+
+// apply remplace l'attente configurée par les options passées
+func (f *waitFlags) apply(wait bool, timeout time.Duration) (bool, time.Duration) {
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "wait":
+			wait = *f.wait
+		case "wait-timeout":
+			wait, timeout = true, *f.timeout
+		}
+	})
+	return wait, timeout
+}
+
+// projectConfig retourne la config d'un projet (vide si absente ou illisible)
+func projectConfig(projectName string) config.ProjectConfig {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return config.ProjectConfig{}
+	}
+	return cfg.GetProjectConfig(projectName)
+}
+
+// composeOptions sont les options compose communes aux commandes projet
+type composeOptions struct {
+	profiles stringList
+	envFiles stringList
+}
+
+// addComposeFlags déclare --profile et --env-file sur la commande
+func addComposeFlags(fs *flag.FlagSet) *composeOptions {
+	opts := &composeOptions{}
+	fs.Var(&opts.profiles, "profile", "Active un profil compose (répétable, ou liste séparée par des virgules)")
+	fs.Var(&opts.envFiles, "env-file", "Fichier d'environnement passé à compose (répétable, ex: .env.local)")
+	return opts
+}
+
+// apply remplace les réglages par défaut du projet par ceux de la ligne de commande
+func (o *composeOptions) apply(p *project.Project) {
+	if o == nil {
+		return
+	}
+	if len(o.profiles) > 0 {
+		p.Profiles = o.profiles
+	}
+	if len(o.envFiles) > 0 {
+		p.EnvFiles = o.envFiles
+	}
+}
+
 func printHelp() {
 	fmt.Println(`Docker Manager v1.0.0
 
@@ -132,121 +694,288 @@ Usage:
   docker-manager <command> [options]
 
 Commands:
-  start <project>          Démarre un projet (build + container)
-  stop <project>           Arrête et supprime les containers
-  restart <project>        Redémarre un projet (sans rebuild)
+  start <project> [svc...] Démarre un projet (build + container)
+                           Options: --no-build, --build, --pull, --no-cache, --force-recreate,
+                           --ignore-port-conflicts (avertit au lieu de refuser),
+                           --wait, --wait-timeout 2m (attend que les services soient sains),
+                           --no-deps (ne démarre pas les projets de depends_on)
+  stop <project> [svc...]  Arrête et supprime les containers (down)
+                           Options: --keep (stop sans down), --rm (services: supprime les containers)
+  restart <project> [svc...] Redémarre un projet ou des services (sans rebuild)
+                           Options: --recreate (down/up), --build (rebuild + recreate), --wait
   status [project]         Affiche le statut (global ou d'un projet)
+  deps [project]           Affiche les dépendances entre projets (depends_on) et l'ordre de démarrage
   logs <project> [service] Affiche les logs
                            Options: -f (follow en temps réel)
+  exec <project> <svc> -- <cmd>  Exécute une commande dans un service
+                           Options: -T (sans TTY), --user, --workdir
+  shell <project> [svc]    Ouvre un shell (bash, sh ou ash) dans un service
+  run <project> <task>     Exécute une tâche nommée (compose run --rm)
+  tasks [project]          Liste les tâches définies dans projects.yml
+  check [project]          Sonde les URLs et health_check (code 1 en cas d'échec)
+  open <project> [svc]     Ouvre l'URL du projet ou du service dans le navigateur
+  stats [project]          Consommation CPU, mémoire, réseau et disque par projet/service
+                           Options: -f (rafraîchit toutes les --interval, 5s par défaut)
+  clean <project>          Nettoie un projet (containers orphelins, images, volumes)
+                           Options: --level orphans|images|volumes|all, --include-running, --dry-run, --yes
+  backup <project> [vol...] Sauvegarde les volumes nommés (~/.docker-manager/backups)
+  restore <project> <vol> [archive] Restaure un volume (dernière archive par défaut)
+                           Options: --yes (sans confirmation)
+  backups [project]        Liste les sauvegardes
+  du                       Espace disque par projet (images, containers, volumes, cache)
+  events [project]         Suit en direct les événements des containers
   daemon <start|stop|status> Gère le daemon Docker
   dashboard                Lance le dashboard interactif
 
 Exemples:
   docker-manager start pbwww
+  docker-manager start pbwww --profile debug --profile mail
+  docker-manager start pbwww --env-file .env.test
+  docker-manager start pbwww db redis --no-build
   docker-manager stop pbwww
   docker-manager restart pbwww nginx
+  docker-manager restart pbwww --build     # down, build, up
+  docker-manager start pbwww --wait        # bloque jusqu'à ce que tout soit healthy
+  docker-manager stop pbwww worker --rm
+  docker-manager start checkout            # groupe de projets, en parallèle
+  docker-manager stop --all
+  docker-manager deps pbwww                # arbre des dépendances, ordre de démarrage
   docker-manager status                    # Tous les projets
   docker-manager status pbwww              # Détail d'un projet
   docker-manager logs pbwww -f
+  docker-manager exec pbwww app -- php artisan migrate
+  docker-manager shell pbwww app
+  docker-manager run pbwww migrate
+  docker-manager run pbwww test -- --filter UserTest
+  docker-manager check pbwww               # HTTP status, latence, TLS
+  docker-manager open pbwww                # url de projects.yml, sinon 1re URL publiée
+  docker-manager open pbwww adminer
+  docker-manager stats                     # Tous les projets, triés par mémoire
+  docker-manager stats pbwww -f
+  docker-manager du
+  docker-manager backup pbwww db
+  docker-manager restore pbwww db          # dernière archive
+  docker-manager restore pbwww db 20240131 # archive du 31 janvier
+  docker-manager clean pbwww --level images --dry-run
+  docker-manager clean pbwww --level volumes   # demande confirmation
+  docker-manager events pbwww              # start, die, health_status, oom...
   docker-manager daemon status             # Check Docker daemon
   docker-manager daemon start              # Démarrer Docker daemon
   docker-manager daemon stop               # Arrêter Docker daemon
   docker-manager dashboard
 
+Options des commandes projet (start, stop, restart, status, logs):
+  --profile <nom>         Active un profil compose (répétable)
+  --env-file <fichier>    Fichier d'environnement compose (répétable)
+
+Groupes (start, stop, restart, status):
+  <groupe>                Groupe de projets défini dans projects.yml (groups:)
+  --all                   Tous les projets découverts
+  --parallel <n>          Opérations simultanées (défaut: parallelism, sinon 4)
+
 Options:
   -h, --help              Affiche cette aide
   -v, --version           Affiche la version
-`)
-}
 
-func handleStart(projectName string) error {
-	if err := docker.EnsureDockerRunning(); err != nil {
-		return err
-	}
+Environnement:
+  DOCKER_MANAGER_ROOT     Répertoire racine des projets
+  DOCKER_MANAGER_COMPOSE  Force compose: auto, v1 (docker-compose) ou v2 (docker compose)`)
+}
 
+// findProject découvre les projets et retourne celui qui porte ce nom
+func findProject(projectName string) (*project.Project, error) {
 	projects, err := discovery.DiscoverInDefaultPath()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var targetProject *project.Project
 	for i := range projects {
 		if projects[i].Name == projectName {
-			targetProject = &projects[i]
-			break
+			return &projects[i], nil
 		}
 	}
 
-	if targetProject == nil {
-		return fmt.Errorf("projet '%s' non trouvé", projectName)
-	}
-
-	mgr := docker.NewManager(targetProject.Path)
-	return mgr.StartProject(targetProject)
+	return nil, fmt.Errorf("projet '%s' non trouvé", projectName)
 }
 
-func handleStop(projectName string) error {
-	if err := docker.EnsureDockerRunning(); err != nil {
+func handleStart(ctx context.Context, projectName string, services []string, opts *composeOptions, flags *startFlags) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
 		return err
 	}
 
-	projects, err := discovery.DiscoverInDefaultPath()
-	if err != nil {
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
 		return err
 	}
 
-	var targetProject *project.Project
-	for i := range projects {
-		if projects[i].Name == projectName {
-			targetProject = &projects[i]
-			break
+	if !*flags.noDeps {
+		if err := startDependencies(ctx, projectName); err != nil {
+			return err
 		}
 	}
 
-	if targetProject == nil {
-		return fmt.Errorf("projet '%s' non trouvé", projectName)
-	}
+	cfg := projectConfig(projectName)
+	startOpts := flags.apply(docker.StartOptionsFromConfig(cfg.Start), services)
+	startOpts.HealthChecks = docker.HealthChecksFromConfig(cfg.Services)
 
-	mgr := docker.NewManager(targetProject.Path)
-	return mgr.StopProject(targetProject)
+	return mgr.StartProject(ctx, targetProject, startOpts)
 }
 
-func handleRestart(projectName string, serviceName string) error {
-	if err := docker.EnsureDockerRunning(); err != nil {
+// startDependencies démarre les dépendances arrêtées d'un projet, dans l'ordre
+// topologique. Chaque dépendance doit être opérationnelle avant la suivante.
+func startDependencies(ctx context.Context, projectName string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
 		return err
 	}
 
-	projects, err := discovery.DiscoverInDefaultPath()
+	order, err := cfg.StartOrder(projectName)
 	if err != nil {
 		return err
 	}
-
-	var targetProject *project.Project
-	for i := range projects {
-		if projects[i].Name == projectName {
-			targetProject = &projects[i]
-			break
-		}
+	// Le dernier est le projet lui-même, démarré par l'appelant
+	order = order[:len(order)-1]
+	if len(order) == 0 {
+		return nil
 	}
 
-	if targetProject == nil {
-		return fmt.Errorf("projet '%s' non trouvé", projectName)
+	projects, err := discovery.DiscoverInDefaultPath()
+	if err != nil {
+		return err
 	}
+	mgr := docker.NewManager("")
+	mgr.LoadStatuses(ctx, projects)
+
+	for _, name := range order {
+		var dep *project.Project
+		for i := range projects {
+			if projects[i].Name == name {
+				dep = &projects[i]
+				break
+			}
+		}
+		if dep == nil {
+			return fmt.Errorf("projet '%s' (dépendance de %s) non trouvé", name, projectName)
+		}
+		if dep.Running {
+			fmt.Printf("🔗 Dépendance %s déjà démarrée\n", name)
+			continue
+		}
 
-	mgr := docker.NewManager(targetProject.Path)
+		fmt.Printf("🔗 Démarrage de la dépendance %s\n", name)
+		depCfg := cfg.GetProjectConfig(name)
+		startOpts := docker.StartOptionsFromConfig(depCfg.Start)
+		startOpts.Wait = true
+		startOpts.HealthChecks = docker.HealthChecksFromConfig(depCfg.Services)
 
-	// Si pas de service spécifié, on redémarre le projet entier
-	if serviceName == "" {
-		fmt.Printf("🔄 Redémarrage complet du projet %s...\n", projectName)
-		// On peut implémenter un true restart ici
-		return mgr.RestartService(targetProject, "")
+		depMgr := docker.NewManager(dep.Path)
+		if err := depMgr.StartProject(ctx, dep, startOpts); err != nil {
+			return fmt.Errorf("dépendance %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func handleStop(ctx context.Context, projectName string, stopOpts docker.StopOptions, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	if len(stopOpts.Services) == 0 {
+		warnRunningDependents(ctx, []string{projectName})
+	}
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	return mgr.StopProject(ctx, targetProject, stopOpts)
+}
+
+// warnRunningDependents avertit quand des projets qui dépendent de ceux arrêtés
+// tournent encore (les projets arrêtés ensemble ne sont pas signalés)
+func warnRunningDependents(ctx context.Context, stopped []string) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return
+	}
+	dependents := make(map[string][]string)
+	for _, name := range stopped {
+		for _, dependent := range cfg.Dependents(name) {
+			if !containsString(stopped, dependent) {
+				dependents[name] = append(dependents[name], dependent)
+			}
+		}
+	}
+	if len(dependents) == 0 {
+		return
+	}
+
+	projects, err := discovery.DiscoverInDefaultPath()
+	if err != nil {
+		return
+	}
+	mgr := docker.NewManager("")
+	mgr.LoadStatuses(ctx, projects)
+
+	for _, name := range stopped {
+		var running []string
+		for _, p := range projects {
+			if p.Running && containsString(dependents[name], p.Name) {
+				running = append(running, p.Name)
+			}
+		}
+		if len(running) > 0 {
+			logger.Warn(fmt.Sprintf("%s est une dépendance de projets encore démarrés", name),
+				"projets", strings.Join(running, ", "))
+		}
+	}
+}
+
+// containsString indique si la liste contient la valeur
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func handleRestart(ctx context.Context, projectName string, restartOpts docker.RestartOptions, wait *waitFlags, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	cfg := projectConfig(projectName)
+	defaults := docker.StartOptionsFromConfig(cfg.Start)
+	restartOpts.Wait, restartOpts.WaitTimeout = wait.apply(defaults.Wait, defaults.WaitTimeout)
+	restartOpts.HealthChecks = docker.HealthChecksFromConfig(cfg.Services)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
 	}
 
-	return mgr.RestartService(targetProject, serviceName)
+	// Sans service, RestartProject redémarre le projet entier
+	return mgr.RestartProject(ctx, targetProject, restartOpts)
 }
 
-func handleStatus() error {
-	if err := docker.EnsureDockerRunning(); err != nil {
+// runBatch exécute op sur chaque projet (au plus parallel à la fois), dans l'ordre
+// donné pour les dépendances (after), puis affiche le résumé par projet
+func runBatch(ctx context.Context, action string, names []string, parallel int, after func(string) []string,
+	op func(ctx context.Context, p *project.Project, mgr *docker.Manager) error) error {
+	if err := docker.NewManager("").EnsureDockerRunning(ctx); err != nil {
 		return err
 	}
 
@@ -254,28 +983,163 @@ func handleStatus() error {
 	if err != nil {
 		return err
 	}
+	byName := make(map[string]*project.Project, len(projects))
+	for i := range projects {
+		byName[projects[i].Name] = &projects[i]
+	}
 
-	fmt.Println("\n📊 Statut des projets Docker")
+	fmt.Printf("📦 %s de %d projet(s) : %s (%d en parallèle)\n", action, len(names), strings.Join(names, ", "), parallel)
+
+	var output batch.Output
+	results := batch.Run(ctx, names, batch.Options{Parallelism: parallel, After: after},
+		func(ctx context.Context, name string) error {
+			p, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("projet '%s' non trouvé", name)
+			}
+
+			mgr := docker.NewManager(p.Path)
+			if parallel > 1 {
+				// Sorties préfixées par projet pour ne pas mélanger les lignes
+				stdout := output.Writer(os.Stdout, "["+name+"]")
+				stderr := output.Writer(os.Stderr, "["+name+"]")
+				defer stdout.Flush()
+				defer stderr.Flush()
+				mgr.Stdin, mgr.Stdout, mgr.Stderr = nil, stdout, stderr
+			}
+			return op(ctx, p, mgr)
+		})
+
+	printBatchSummary(results)
+	if batch.Failed(results) > 0 {
+		return errBatchFailed
+	}
+	return nil
+}
+
+// printBatchSummary affiche le résultat de chaque projet d'un lot
+func printBatchSummary(results []batch.Result) {
 	fmt.Println("─────────────────────────────────────────")
+	fmt.Printf("Résumé : %d/%d projet(s) OK\n", len(results)-batch.Failed(results), len(results))
+	for _, result := range results {
+		if result.Err == nil {
+			fmt.Printf("  ✅ %-20s %s\n", result.Project, result.Duration.Round(time.Second))
+			continue
+		}
+		// Première ligne de l'erreur : le détail a déjà été affiché par compose
+		message := strings.SplitN(result.Err.Error(), "\n", 2)[0]
+		fmt.Printf("  ❌ %-20s %s\n", result.Project, message)
+	}
+}
 
-	mgr := docker.NewManager("")
+// handleBatchStart démarre un groupe de projets, dépendances comprises (dans l'ordre topologique)
+func handleBatchStart(ctx context.Context, names []string, opts *composeOptions, flags *startFlags, parallel int) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
 
-	for _, p := range projects {
-		running, count, _ := mgr.GetStatus(&p)
+	order := names
+	after := cfg.Dependencies
+	if *flags.noDeps {
+		after = nil
+	} else if order, err = cfg.StartOrder(names...); err != nil {
+		return err
+	}
 
-		if running {
-			fmt.Printf("  %-20s ▶  Running (%d services)\n", p.Name, count)
-		} else {
-			fmt.Printf("  %-20s ⏹  Stopped\n", p.Name)
+	// Une dépendance doit être opérationnelle avant le démarrage des projets qui l'utilisent
+	needed := make(map[string]bool)
+	if after != nil {
+		for _, name := range order {
+			for _, dep := range cfg.Dependencies(name) {
+				needed[dep] = true
+			}
 		}
 	}
 
-	fmt.Println("─────────────────────────────────────────\n")
-	return nil
+	return runBatch(ctx, "Démarrage", order, parallel, after,
+		func(ctx context.Context, p *project.Project, mgr *docker.Manager) error {
+			if !containsString(names, p.Name) {
+				// Dépendance hors du groupe : laissée telle quelle si elle tourne déjà
+				if running, _, _ := mgr.GetStatus(ctx, p); running {
+					fmt.Fprintf(mgr.Stdout, "🔗 Dépendance %s déjà démarrée\n", p.Name)
+					return nil
+				}
+			}
+
+			opts.apply(p)
+			projectCfg := cfg.GetProjectConfig(p.Name)
+			startOpts := flags.apply(docker.StartOptionsFromConfig(projectCfg.Start), nil)
+			startOpts.HealthChecks = docker.HealthChecksFromConfig(projectCfg.Services)
+			if needed[p.Name] {
+				startOpts.Wait = true
+			}
+			return mgr.StartProject(ctx, p, startOpts)
+		})
+}
+
+// handleBatchStop arrête un groupe de projets, ceux qui dépendent des autres en premier
+func handleBatchStop(ctx context.Context, names []string, stopOpts docker.StopOptions, opts *composeOptions, parallel int) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Ordre de démarrage inversé ; en cas de cycle, l'ordre du groupe est gardé
+	order := names
+	if startOrder, err := cfg.StartOrder(names...); err == nil {
+		order = nil
+		for i := len(startOrder) - 1; i >= 0; i-- {
+			if containsString(names, startOrder[i]) {
+				order = append(order, startOrder[i])
+			}
+		}
+	}
+
+	warnRunningDependents(ctx, order)
+
+	return runBatch(ctx, "Arrêt", order, parallel, cfg.Dependents,
+		func(ctx context.Context, p *project.Project, mgr *docker.Manager) error {
+			opts.apply(p)
+			return mgr.StopProject(ctx, p, stopOpts)
+		})
+}
+
+// handleBatchRestart redémarre un groupe de projets, dépendances d'abord
+func handleBatchRestart(ctx context.Context, names []string, restartOpts docker.RestartOptions, wait *waitFlags, opts *composeOptions, parallel int) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Comme au démarrage, un cycle de dépendances est une erreur de configuration
+	startOrder, err := cfg.StartOrder(names...)
+	if err != nil {
+		return err
+	}
+	var order []string
+	for _, name := range startOrder {
+		if containsString(names, name) {
+			order = append(order, name)
+		}
+	}
+
+	return runBatch(ctx, "Redémarrage", order, parallel, cfg.Dependencies,
+		func(ctx context.Context, p *project.Project, mgr *docker.Manager) error {
+			opts.apply(p)
+			projectCfg := cfg.GetProjectConfig(p.Name)
+			defaults := docker.StartOptionsFromConfig(projectCfg.Start)
+			projectOpts := restartOpts
+			projectOpts.Wait, projectOpts.WaitTimeout = wait.apply(defaults.Wait, defaults.WaitTimeout)
+			projectOpts.HealthChecks = docker.HealthChecksFromConfig(projectCfg.Services)
+			return mgr.RestartProject(ctx, p, projectOpts)
+		})
 }
 
-func handleStatusProject(projectName string) error {
-	if err := docker.EnsureDockerRunning(); err != nil {
+// handleStatus affiche le statut de tous les projets, ou de ceux listés (groupe)
+func handleStatus(ctx context.Context, names []string, opts *composeOptions) error {
+	mgr := docker.NewManager("")
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
 		return err
 	}
 
@@ -284,26 +1148,65 @@ func handleStatusProject(projectName string) error {
 		return err
 	}
 
-	var targetProject *project.Project
+	// Groupe : seuls ses projets sont affichés, les absents sont signalés
+	if names != nil {
+		var selected []project.Project
+		for _, name := range names {
+			found := false
+			for _, p := range projects {
+				if p.Name == name {
+					selected = append(selected, p)
+					found = true
+				}
+			}
+			if !found {
+				logger.Warn(fmt.Sprintf("projet '%s' non trouvé", name))
+			}
+		}
+		projects = selected
+	}
+	// --profile/--env-file s'appliquent à chaque projet affiché
 	for i := range projects {
-		if projects[i].Name == projectName {
-			targetProject = &projects[i]
-			break
+		opts.apply(&projects[i])
+	}
+
+	fmt.Println("\n📊 Statut des projets Docker")
+	fmt.Println("─────────────────────────────────────────")
+
+	mgr.LoadStatuses(ctx, projects)
+
+	for _, p := range projects {
+		if p.Running {
+			fmt.Printf("  %-20s ▶  Running (%d services)\n", p.Name, p.ServiceCount)
+		} else {
+			fmt.Printf("  %-20s ⏹  Stopped\n", p.Name)
 		}
 	}
 
-	if targetProject == nil {
-		return fmt.Errorf("projet '%s' non trouvé", projectName)
+	fmt.Println("─────────────────────────────────────────")
+	fmt.Println()
+	return nil
+}
+
+func handleStatusProject(ctx context.Context, projectName string, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
 	}
 
+	opts.apply(targetProject)
+
 	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
 
 	fmt.Println()
 	fmt.Printf("📊 Status détaillé : %s\n", targetProject.Name)
 	fmt.Println("─────────────────────────────────────────")
 
 	// Utiliser GetStatusDetailed pour avoir plus d'infos
-	running, _, statusMsg := mgr.GetStatusDetailed(targetProject)
+	running, _, statusMsg := mgr.GetStatusDetailed(ctx, targetProject)
 
 	if running {
 		fmt.Printf("  Status   : ▶ %s\n", statusMsg)
@@ -311,49 +1214,106 @@ func handleStatusProject(projectName string) error {
 		fmt.Printf("  Status   : ⏹ %s\n", statusMsg)
 	}
 
-	// Essayer de récupérer les services
-	services, err := mgr.GetServices(targetProject)
-	if err == nil && len(services) > 0 {
-		fmt.Printf("  Services : %v\n", services)
-	}
-
-	urlsByService, err := mgr.GetServiceURLs(targetProject)
-	if err == nil && len(urlsByService) > 0 {
-		fmt.Println("  URLs     :")
-		if len(services) > 0 {
-			for _, service := range services {
-				urls, ok := urlsByService[service]
-				if !ok {
-					continue
-				}
-				for _, url := range urls {
-					fmt.Printf("    - %s => %s\n", service, url)
-				}
+	// Essayer de récupérer l'état de chaque service
+	var services []string
+	statuses, err := mgr.GetServiceStatuses(ctx, targetProject)
+	if err == nil && len(statuses) > 0 {
+		targetProject.Services = statuses
+		fmt.Printf("  Santé    : %s\n", targetProject.HealthSummary())
+		fmt.Println("  Services :")
+		for _, service := range statuses {
+			services = appendService(services, service.Name)
+			line := fmt.Sprintf("    - %-15s %-14s", service.Name, service.StateString())
+			if uptime := service.Uptime(); uptime > 0 {
+				line += fmt.Sprintf(" up %-10s", uptime)
 			}
-		} else {
-			for service, urls := range urlsByService {
-				for _, url := range urls {
-					fmt.Printf("    - %s => %s\n", service, url)
-				}
+			if service.Container != "" {
+				line += " " + service.Container
 			}
+			fmt.Println(line)
+		}
+	}
+
+	// URLs HTTP(S), puis les autres ports publiés en hôte:port
+	if endpointsByService, err := mgr.GetServiceEndpoints(ctx, targetProject); err == nil && len(endpointsByService) > 0 {
+		extra := make([]string, 0, len(endpointsByService))
+		for service := range endpointsByService {
+			extra = append(extra, service)
+		}
+		sort.Strings(extra)
+		for _, service := range extra {
+			services = appendService(services, service)
+		}
+		printEndpoints("URLs     :", services, endpointsByService, true)
+		printEndpoints("Ports    :", services, endpointsByService, false)
+	}
+
+	// Sonder les URLs publiées et les health_check configurés
+	if running {
+		checks := docker.HealthChecksFromConfig(projectConfig(projectName).Services)
+		if probes, err := mgr.ProbeServices(ctx, targetProject, checks); err == nil && len(probes) > 0 {
+			fmt.Println("  Sondes   :")
+			printProbes(probes, "    ")
 		}
 	}
 
 	// Afficher le chemin du projet
 	fmt.Printf("  Path     : %s\n", targetProject.Path)
-	fmt.Printf("  Compose  : %s\n", targetProject.ComposePath)
+	fmt.Printf("  Compose  : %s\n", strings.Join(targetProject.Files(), ", "))
+	if len(targetProject.Profiles) > 0 {
+		fmt.Printf("  Profils  : %s\n", strings.Join(targetProject.Profiles, ", "))
+	}
+	if sources := targetProject.EnvSources(); len(sources) > 0 {
+		fmt.Printf("  Env      : %s\n", strings.Join(sources, " + "))
+	}
+	if available, err := mgr.GetProfiles(ctx, targetProject); err == nil && len(available) > 0 {
+		fmt.Printf("  Profils disponibles : %s\n", strings.Join(available, ", "))
+	}
 
 	// Vérifier que les fichiers existent
-	if _, err := os.Stat(targetProject.ComposePath); os.IsNotExist(err) {
-		fmt.Printf("  ⚠️  docker-compose.yml manquant!\n")
+	for _, file := range targetProject.MissingFiles() {
+		fmt.Printf("  ⚠️  %s manquant!\n", file)
 	}
 
-	fmt.Println("─────────────────────────────────────────\n")
+	fmt.Println("─────────────────────────────────────────")
+	fmt.Println()
 	return nil
 }
 
-func handleLogs(projectName string, serviceName string, follow bool) error {
-	if err := docker.EnsureDockerRunning(); err != nil {
+// printEndpoints affiche les URLs (urls=true) ou les autres ports publiés, par service
+func printEndpoints(title string, services []string, endpointsByService map[string][]docker.Endpoint, urls bool) {
+	printed := false
+	for _, service := range services {
+		for _, endpoint := range endpointsByService[service] {
+			if (endpoint.URL != "") != urls {
+				continue
+			}
+			if !printed {
+				fmt.Printf("  %s\n", title)
+				printed = true
+			}
+			fmt.Printf("    - %s => %s\n", service, endpoint)
+		}
+	}
+}
+
+// printProbes affiche le résultat de chaque sonde
+func printProbes(probes []docker.ServiceProbe, indent string) {
+	for _, probe := range probes {
+		icon := "✅"
+		if !probe.OK {
+			icon = "❌"
+		}
+		fmt.Printf("%s%s %-15s %-32s %s\n", indent, icon, probe.Service, probe.Target, probe.Result)
+	}
+}
+
+// errCheckFailed signale qu'au moins une sonde a échoué (code de sortie 1)
+var errCheckFailed = errors.New("sondes en échec")
+
+func handleCheck(ctx context.Context, projectName string) error {
+	mgr := docker.NewManager("")
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
 		return err
 	}
 
@@ -362,24 +1322,633 @@ func handleLogs(projectName string, serviceName string, follow bool) error {
 		return err
 	}
 
-	var targetProject *project.Project
+	mgr.LoadStatuses(ctx, projects)
+
+	failed := false
+	checked := 0
 	for i := range projects {
-		if projects[i].Name == projectName {
-			targetProject = &projects[i]
-			break
+		p := &projects[i]
+		if projectName != "" && p.Name != projectName {
+			continue
+		}
+		if !p.Running {
+			if projectName != "" {
+				fmt.Printf("❌ %s n'est pas démarré\n", p.Name)
+				return errCheckFailed
+			}
+			continue
+		}
+		checked++
+
+		checks := docker.HealthChecksFromConfig(projectConfig(p.Name).Services)
+		probes, err := mgr.ProbeServices(ctx, p, checks)
+		if err != nil {
+			fmt.Printf("❌ %s : %v\n", p.Name, err)
+			failed = true
+			continue
+		}
+
+		fmt.Printf("📦 %s\n", p.Name)
+		if len(probes) == 0 {
+			fmt.Println("  (aucun port publié ni health_check)")
+		}
+		printProbes(probes, "  ")
+		for _, probe := range probes {
+			failed = failed || !probe.OK
 		}
 	}
 
-	if targetProject == nil {
+	if projectName != "" && checked == 0 {
 		return fmt.Errorf("projet '%s' non trouvé", projectName)
 	}
+	if checked == 0 {
+		fmt.Println("Aucun projet démarré")
+	}
+	if failed {
+		return errCheckFailed
+	}
+	return nil
+}
+
+// appendService ajoute un nom de service s'il n'est pas déjà présent
+func appendService(services []string, name string) []string {
+	for _, existing := range services {
+		if existing == name {
+			return services
+		}
+	}
+	return append(services, name)
+}
+
+func handleOpen(ctx context.Context, projectName string, service string, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	target, err := mgr.PrimaryURL(ctx, targetProject, service)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🌐 Ouverture de %s\n", target)
+	return browser.Open(cfg.Opener, target)
+}
+
+func handleLogs(ctx context.Context, projectName string, serviceName string, follow bool, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	return mgr.GetLogs(ctx, targetProject, serviceName, follow)
+}
+
+func handleExec(ctx context.Context, projectName string, service string, command []string, execOpts docker.ExecOptions, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	return mgr.Exec(ctx, targetProject, service, command, execOpts)
+}
+
+func handleShell(ctx context.Context, projectName string, service string, shell string, execOpts docker.ExecOptions, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	cfg := projectConfig(projectName)
+
+	// Service : argument, sinon default_service, sinon l'unique service du projet
+	if service == "" {
+		service = cfg.DefaultService
+	}
+	if service == "" {
+		services, err := mgr.GetServices(ctx, targetProject)
+		if err != nil {
+			return err
+		}
+		if len(services) != 1 {
+			return fmt.Errorf("précisez le service parmi: %s (ou default_service dans projects.yml)", strings.Join(services, ", "))
+		}
+		service = services[0]
+	}
+
+	if shell == "" {
+		shell = cfg.Shell
+	}
+	return mgr.Shell(ctx, targetProject, service, shell, execOpts)
+}
+
+func handleRun(ctx context.Context, projectName string, taskName string, extraArgs []string, execOpts docker.ExecOptions, opts *composeOptions) error {
+	cfg := projectConfig(projectName)
+	taskCfg, ok := cfg.Tasks[taskName]
+	if !ok {
+		return fmt.Errorf("tâche '%s' non définie pour %s (voir: docker-manager tasks %s)", taskName, projectName, projectName)
+	}
+
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	return mgr.RunTask(ctx, targetProject, docker.TaskFromConfig(taskName, taskCfg), extraArgs, execOpts)
+}
+
+func handleTasks(projectName string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(cfg.Projects))
+	if projectName != "" {
+		names = append(names, projectName)
+	} else {
+		for name, projectCfg := range cfg.Projects {
+			if len(projectCfg.Tasks) > 0 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	if len(names) == 0 {
+		fmt.Println("Aucune tâche définie (section tasks: d'un projet dans projects.yml)")
+		return nil
+	}
+
+	for _, name := range names {
+		tasks := docker.TasksFromConfig(cfg.GetProjectConfig(name))
+		fmt.Printf("📦 %s\n", name)
+		if len(tasks) == 0 {
+			fmt.Println("  (aucune tâche)")
+		}
+		for _, task := range tasks {
+			fmt.Printf("  %-16s %-12s %s\n", task.Name, task.Service, task.CommandString())
+			if task.Description != "" {
+				fmt.Printf("  %-16s %s\n", "", task.Description)
+			}
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func handleStats(ctx context.Context, projectName string, follow bool, interval time.Duration) error {
+	mgr := docker.NewManager("")
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	if projectName != "" {
+		if _, err := findProject(projectName); err != nil {
+			return err
+		}
+	}
+
+	for {
+		stats, err := mgr.GetStats(ctx, projectName)
+		if err != nil && ctx.Err() != nil {
+			return nil
+		}
+		if err != nil && !follow {
+			return err
+		}
+
+		if follow {
+			// Effacer l'écran entre deux rafraîchissements
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("📊 %s (Ctrl-C pour quitter)\n\n", time.Now().Format("15:04:05"))
+		}
+		if err != nil {
+			// En mode rafraîchissement, une erreur passagère n'arrête pas l'affichage
+			fmt.Printf("❌ %v\n", err)
+		} else {
+			printStats(stats)
+		}
+
+		if !follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printStats affiche la consommation par projet puis par service
+func printStats(stats []docker.ProjectStats) {
+	if len(stats) == 0 {
+		fmt.Println("Aucun container en cours d'exécution")
+		return
+	}
+
+	fmt.Printf("%-24s %8s %12s %22s %22s %6s\n", "PROJET / SERVICE", "CPU", "MEM", "NET RX / TX", "BLOCK R / W", "PIDS")
+	line := func(name string, usage docker.ResourceUsage) {
+		fmt.Printf("%-24s %7.1f%% %12s %22s %22s %6d\n",
+			name,
+			usage.CPUPercent,
+			docker.FormatBytes(usage.MemUsage),
+			docker.FormatBytes(usage.NetRx)+" / "+docker.FormatBytes(usage.NetTx),
+			docker.FormatBytes(usage.BlockRead)+" / "+docker.FormatBytes(usage.BlockWrite),
+			usage.PIDs,
+		)
+	}
+
+	var total docker.ResourceUsage
+	for _, project := range stats {
+		total.Add(project.Total)
+		line(project.Name, project.Total)
+		for _, service := range project.Services {
+			name := "  " + service.Name
+			if service.Containers > 1 {
+				name += fmt.Sprintf(" (x%d)", service.Containers)
+			}
+			line(name, service.ResourceUsage)
+		}
+	}
+
+	if len(stats) > 1 {
+		fmt.Println()
+		line("Total", total)
+	}
+	if total.MemLimit > 0 {
+		fmt.Printf("\nMémoire disponible pour Docker : %s\n", docker.FormatBytes(total.MemLimit))
+	}
+}
+
+func handleDu(ctx context.Context) error {
+	mgr := docker.NewManager("")
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	projects, err := discovery.DiscoverInDefaultPath()
+	if err != nil {
+		return err
+	}
+	discovered := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		discovered[p.Name] = true
+	}
+
+	fmt.Println("💾 Calcul de l'espace disque...")
+	report, err := mgr.GetDiskUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	size := func(value int64) string {
+		if value <= 0 {
+			return "-"
+		}
+		return docker.FormatBytes(uint64(value))
+	}
+	line := func(name string, usage docker.DiskUsage) {
+		fmt.Printf("%-24s %12s %12s %12s %12s %12s\n", name,
+			size(usage.Images), size(usage.Containers), size(usage.Volumes), size(usage.BuildCache), size(usage.Total()))
+	}
+
+	fmt.Printf("\n%-24s %12s %12s %12s %12s %12s\n", "PROJET", "IMAGES", "CONTAINERS", "VOLUMES", "CACHE BUILD", "TOTAL")
+	var total docker.DiskUsage
+	hidden := false
+	for _, p := range report.Projects {
+		name := p.Name
+		if !discovered[name] {
+			name += " *"
+			hidden = true
+		}
+		line(name, p.DiskUsage)
+		total.Add(p.DiskUsage)
+	}
+	if report.Shared.Total() > 0 {
+		line("(images partagées)", report.Shared)
+		total.Add(report.Shared)
+	}
+	line("(hors projet)", report.Unattributed)
+	total.Add(report.Unattributed)
+
+	fmt.Println()
+	line("Total", total)
+	if hidden {
+		fmt.Println("\n* projet compose non découvert dans le répertoire racine")
+	}
+	return nil
+}
+
+func handleClean(ctx context.Context, projectName string, level docker.CleanLevel, includeRunning bool, dryRun bool, yes bool, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
 
 	mgr := docker.NewManager(targetProject.Path)
-	return mgr.GetLogs(targetProject, serviceName, follow)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	plan, err := mgr.PlanClean(ctx, targetProject, level, includeRunning)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🧹 Nettoyage de %s (niveau %s)\n", projectName, level)
+	if plan.Empty() {
+		fmt.Println("Rien à supprimer")
+		printKeptContainers(plan)
+		return nil
+	}
+	printCleanPlan(plan)
+	printKeptContainers(plan)
+
+	if dryRun {
+		fmt.Println("\nSimulation (--dry-run) : rien n'a été supprimé")
+		return nil
+	}
+
+	// Les volumes contiennent les données du projet et les containers démarrés
+	// seront arrêtés de force : confirmation obligatoire
+	var warnings []string
+	if running := plan.RunningContainers(); running > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d container(s) démarré(s) seront arrêtés", running))
+	}
+	if len(plan.Volumes) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d volume(s) et leurs données seront supprimés définitivement", len(plan.Volumes)))
+	}
+	if len(warnings) > 0 && !yes {
+		if !stdinIsTerminal() {
+			return fmt.Errorf("%s : confirmation requise, relancez avec --yes", strings.Join(warnings, ", "))
+		}
+		fmt.Printf("\n⚠️  %s. Continuer ? [o/N] ", strings.Join(warnings, ", "))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "o", "oui", "y", "yes":
+		default:
+			fmt.Println("Annulé")
+			return nil
+		}
+	}
+
+	fmt.Println()
+	return mgr.Clean(ctx, targetProject, plan)
+}
+
+// printCleanPlan affiche chaque ressource qui sera supprimée
+func printCleanPlan(plan *docker.CleanPlan) {
+	sections := []struct {
+		title string
+		items []docker.CleanItem
+	}{
+		{"Containers", plan.Containers},
+		{"Réseaux", plan.Networks},
+		{"Images", plan.Images},
+		{"Volumes", plan.Volumes},
+	}
+
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d):\n", section.title, len(section.items))
+		for _, item := range section.items {
+			line := fmt.Sprintf("  - %-40s %s", item.Name, item.ID)
+			if item.Name == item.ID {
+				line = fmt.Sprintf("  - %-40s", item.Name)
+			}
+			if item.Size > 0 {
+				line += "  " + docker.FormatBytes(uint64(item.Size))
+			}
+			if item.Note != "" {
+				line += "  (" + item.Note + ")"
+			}
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+	}
+
+	if size := plan.Size(); size > 0 {
+		fmt.Printf("\nEspace libéré estimé : %s\n", docker.FormatBytes(uint64(size)))
+	}
+}
+
+// printKeptContainers signale les containers démarrés exclus du nettoyage
+func printKeptContainers(plan *docker.CleanPlan) {
+	if len(plan.Kept) == 0 {
+		return
+	}
+	fmt.Printf("\nConservés car démarrés (%d) :\n", len(plan.Kept))
+	for _, item := range plan.Kept {
+		fmt.Printf("  - %-40s %s  (%s)\n", item.Name, item.ID, item.Note)
+	}
+	fmt.Println("Arrêtez le projet ou relancez avec --include-running pour les supprimer")
 }
 
-func handleDashboard() error {
-	if err := docker.EnsureDockerRunning(); err != nil {
+func handleBackup(ctx context.Context, projectName string, volumeNames []string, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	cfg := projectConfig(projectName).Backup
+	retention, err := docker.RetentionFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	// Volumes : arguments, sinon backup.volumes, sinon tous les volumes du projet
+	if len(volumeNames) == 0 {
+		volumeNames = cfg.Volumes
+	}
+	var volumes []docker.ProjectVolume
+	if len(volumeNames) == 0 {
+		if volumes, err = mgr.ProjectVolumes(ctx, targetProject); err != nil {
+			return err
+		}
+		if len(volumes) == 0 {
+			return fmt.Errorf("le projet %s n'a aucun volume nommé (a-t-il déjà été démarré ?)", projectName)
+		}
+	}
+	for _, name := range volumeNames {
+		volume, err := mgr.FindVolume(ctx, targetProject, name)
+		if err != nil {
+			return err
+		}
+		volumes = append(volumes, volume)
+	}
+
+	for _, volume := range volumes {
+		if _, err := mgr.BackupVolume(ctx, targetProject, volume, config.BackupsDir()); err != nil {
+			return err
+		}
+	}
+
+	backups, err := docker.ListBackups(config.BackupsDir(), projectName)
+	if err != nil {
+		return err
+	}
+	removed, err := docker.PruneBackups(backups, retention, time.Now())
+	for _, backup := range removed {
+		fmt.Printf("🗑  Archive expirée supprimée : %s\n", filepath.Base(backup.Path))
+	}
+	return err
+}
+
+func handleRestore(ctx context.Context, projectName string, volumeName string, archive string, yes bool, opts *composeOptions) error {
+	targetProject, err := findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	opts.apply(targetProject)
+
+	mgr := docker.NewManager(targetProject.Path)
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	volume, err := mgr.FindVolume(ctx, targetProject, volumeName)
+	var notFound *docker.VolumeNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		// Volume supprimé (clean, down -v) : le recréer sous son nom compose
+		volume = docker.ComposeVolume(targetProject, volumeName)
+	case err != nil:
+		return err
+	}
+
+	backup, err := findBackup(targetProject.Name, volume.Short, archive)
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		if !stdinIsTerminal() {
+			return fmt.Errorf("restauration de %s : confirmation requise, relancez avec --yes", volume.Short)
+		}
+		fmt.Printf("⚠️  Le contenu actuel du volume %s sera remplacé par l'archive du %s. Continuer ? [o/N] ",
+			volume.Name, backup.Time.Format("2006-01-02 15:04:05"))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "o", "oui", "y", "yes":
+		default:
+			fmt.Println("Annulé")
+			return nil
+		}
+	}
+
+	return mgr.RestoreVolume(ctx, targetProject, volume, backup)
+}
+
+// findBackup retrouve une archive par chemin, nom de fichier ou date ;
+// sans précision, la plus récente du volume
+func findBackup(projectName string, volumeName string, archive string) (docker.Backup, error) {
+	backups, err := docker.ListBackups(config.BackupsDir(), projectName)
+	if err != nil {
+		return docker.Backup{}, err
+	}
+
+	for _, backup := range backups {
+		if backup.Volume != volumeName {
+			continue
+		}
+		if archive == "" ||
+			archive == backup.Path ||
+			archive == filepath.Base(backup.Path) ||
+			strings.HasPrefix(backup.Time.Format("20060102-150405"), archive) {
+			return backup, nil
+		}
+	}
+
+	if archive != "" {
+		return docker.Backup{}, fmt.Errorf("archive '%s' introuvable pour %s/%s (voir: docker-manager backups %s)", archive, projectName, volumeName, projectName)
+	}
+	return docker.Backup{}, fmt.Errorf("aucune sauvegarde du volume %s pour %s", volumeName, projectName)
+}
+
+func handleBackups(projectName string) error {
+	backups, err := docker.ListBackups(config.BackupsDir(), projectName)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Printf("Aucune sauvegarde dans %s\n", config.BackupsDir())
+		return nil
+	}
+
+	current := ""
+	for _, backup := range backups {
+		if backup.Project != current {
+			if current != "" {
+				fmt.Println()
+			}
+			current = backup.Project
+			fmt.Printf("📦 %s\n", current)
+		}
+		fmt.Printf("  %-20s %s  %10s  %s\n",
+			backup.Volume,
+			backup.Time.Format("2006-01-02 15:04:05"),
+			docker.FormatBytes(uint64(backup.Size)),
+			filepath.Base(backup.Path))
+	}
+	return nil
+}
+
+func handleDeps(projectName string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
 		return err
 	}
 
@@ -387,19 +1956,151 @@ func handleDashboard() error {
 	if err != nil {
 		return err
 	}
+	known := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		known[p.Name] = true
+	}
+
+	if projectName != "" {
+		order, err := cfg.StartOrder(projectName)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("📦 %s\n", projectName)
+		if len(order) == 1 {
+			fmt.Println("  (aucune dépendance)")
+		}
+		printDependencyTree(cfg, known, projectName, "  ")
+		fmt.Printf("\nOrdre de démarrage : %s\n", strings.Join(order, " → "))
+		if dependents := cfg.Dependents(projectName); len(dependents) > 0 {
+			fmt.Printf("Utilisé par        : %s\n", strings.Join(dependents, ", "))
+		}
+		return nil
+	}
+
+	if err := cfg.CheckDependencies(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(cfg.Projects))
+	for name := range cfg.Projects {
+		if len(cfg.Dependencies(name)) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println("Aucune dépendance entre projets (depends_on dans projects.yml)")
+		return nil
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("📦 %s\n", name)
+		printDependencyTree(cfg, known, name, "  ")
+	}
+	return nil
+}
+
+// printDependencyTree affiche récursivement les dépendances d'un projet (graphe sans cycle)
+func printDependencyTree(cfg *config.Config, known map[string]bool, projectName string, prefix string) {
+	deps := cfg.Dependencies(projectName)
+	for i, dep := range deps {
+		branch, next := "├─ ", "│  "
+		if i == len(deps)-1 {
+			branch, next = "└─ ", "   "
+		}
+		label := dep
+		if !known[dep] {
+			label += " (⚠ projet non trouvé)"
+		}
+		fmt.Printf("%s%s%s\n", prefix, branch, label)
+		printDependencyTree(cfg, known, dep, prefix+next)
+	}
+}
 
+func handleEvents(ctx context.Context, projectName string) error {
 	mgr := docker.NewManager("")
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
 
-	// Charger les statuts
-	for i := range projects {
-		running, count, _ := mgr.GetStatus(&projects[i])
-		projects[i].Running = running
-		projects[i].ServiceCount = count
+	if projectName != "" {
+		if _, err := findProject(projectName); err != nil {
+			return err
+		}
+	}
+
+	hub := docker.NewEventHub(mgr.Engine)
+	hub.OnError = func(err error) {
+		logger.Warn("Flux d'événements interrompu, nouvelle tentative...", "error", err)
+	}
+	events, unsubscribe := hub.Subscribe(projectName)
+	defer unsubscribe()
+
+	failed := make(chan error, 1)
+	go func() { failed <- hub.Run(ctx) }()
+
+	fmt.Println("👀 En attente d'événements Docker (Ctrl-C pour quitter)...")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-failed:
+			return err
+		case event := <-events:
+			icon := "•"
+			switch event.Type {
+			case docker.EventStart, docker.EventUnpause:
+				icon = "▶"
+			case docker.EventDie, docker.EventOOM, docker.EventKill:
+				icon = "💥"
+			case docker.EventStop, docker.EventDestroy:
+				icon = "⏹"
+			case docker.EventHealth:
+				icon = "🩺"
+			}
+			fmt.Printf("%s %s  %s\n", event.Time.Format("15:04:05"), icon, event)
+		}
 	}
+}
 
-	model := tui.NewModel(projects, mgr)
+func handleDashboard(ctx context.Context) error {
+	mgr := docker.NewManager("")
+	if err := mgr.EnsureDockerRunning(ctx); err != nil {
+		return err
+	}
+
+	projects, err := discovery.DiscoverInDefaultPath()
+	if err != nil {
+		return err
+	}
+
+	// Charger les statuts (projets interrogés en parallèle)
+	mgr.LoadStatuses(ctx, projects)
+	mgr.LoadServiceStatuses(ctx, projects)
+
+	// Mettre à jour les statuts en direct à partir des événements Docker
+	hub := docker.NewEventHub(mgr.Engine)
+	events, unsubscribe := hub.Subscribe("")
+	defer unsubscribe()
+
+	model := tui.NewModel(projects, mgr, events)
 	prog := tea.NewProgram(model)
 
+	// Les coupures du flux s'affichent dans le dashboard (sortie du manager)
+	hub.OnError = func(err error) {
+		fmt.Fprintf(mgr.Stderr, "⚠️  Flux d'événements interrompu : %v\n", err)
+	}
+	go func() {
+		if err := hub.Run(ctx); err != nil {
+			fmt.Fprintf(mgr.Stderr, "⚠️  %v (statuts non mis à jour en direct)\n", err)
+		}
+	}()
+
 	if _, err := prog.Run(); err != nil {
 		return fmt.Errorf("erreur du dashboard: %w", err)
 	}
@@ -407,20 +2108,25 @@ func handleDashboard() error {
 	return nil
 }
 
-func handleDaemon(action string) error {
-	installed, _ := docker.CheckDockerInstallation()
+func handleDaemon(ctx context.Context, action string) error {
+	mgr := docker.NewManager("")
+	installed, _ := mgr.CheckDockerInstallation(ctx)
 	if !installed {
 		fmt.Println("❌ Docker n'est pas installé")
 		fmt.Printf("📖 Téléchargez Docker: %s\n", docker.GetDockerInstallURL())
 		return nil
 	}
 
-	running, _ := docker.CheckDockerDaemonStatus()
+	running, err := mgr.CheckDockerDaemonStatus(ctx)
+	if err != nil {
+		return err
+	}
 
 	switch action {
 	case "status":
 		if running {
 			fmt.Println("✅ Docker daemon est actif")
+			fmt.Printf("🧩 Compose : %s\n", docker.ComposeDescription(docker.DefaultComposeRunner()))
 		} else {
 			fmt.Println("⏹️  Docker daemon est arrêté")
 		}
@@ -430,7 +2136,7 @@ func handleDaemon(action string) error {
 			return nil
 		}
 		fmt.Println("🚀 Démarrage de Docker daemon...")
-		if err := docker.StartDockerDaemon(); err != nil {
+		if err := docker.StartDockerDaemon(ctx); err != nil {
 			return fmt.Errorf("erreur au démarrage du daemon: %w", err)
 		}
 		fmt.Println("✅ Docker daemon a été démarré")
@@ -440,7 +2146,7 @@ func handleDaemon(action string) error {
 			return nil
 		}
 		fmt.Println("🛑 Arrêt de Docker daemon...")
-		if err := docker.StopDockerDaemon(); err != nil {
+		if err := docker.StopDockerDaemon(ctx); err != nil {
 			return fmt.Errorf("erreur à l'arrêt du daemon: %w", err)
 		}
 		fmt.Println("✅ Docker daemon a été arrêté")
@@ -0,0 +1,156 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultParallelism est le nombre d'opérations simultanées par défaut
+const DefaultParallelism = 4
+
+// Result est le résultat de l'opération sur un projet
+type Result struct {
+	Project  string
+	Err      error
+	Duration time.Duration
+}
+
+// Options règle l'exécution d'un lot
+type Options struct {
+	// Parallelism limite les opérations simultanées (DefaultParallelism si <= 0)
+	Parallelism int
+	// After retourne les projets qui doivent avoir terminé avant celui-ci.
+	// Seuls ceux placés plus tôt dans la liste sont attendus (pas d'interblocage).
+	After func(project string) []string
+}
+
+// Run exécute fn pour chaque projet, au plus Parallelism à la fois.
+// Un projet dont une dépendance (After) a échoué n'est pas lancé.
+// Les résultats sont dans l'ordre des projets.
+func Run(ctx context.Context, projects []string, opts Options, fn func(ctx context.Context, project string) error) []Result {
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	index := make(map[string]int, len(projects))
+	for i, name := range projects {
+		index[name] = i
+	}
+
+	results := make([]Result, len(projects))
+	done := make([]chan struct{}, len(projects))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	slots := make(chan struct{}, parallelism)
+
+	var wg sync.WaitGroup
+	for i, name := range projects {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer close(done[i])
+			results[i].Project = name
+
+			if opts.After != nil {
+				for _, dep := range opts.After(name) {
+					j, ok := index[dep]
+					if !ok || j >= i {
+						continue
+					}
+					<-done[j]
+					if results[j].Err != nil {
+						results[i].Err = fmt.Errorf("non lancé : %s en échec", dep)
+						return
+					}
+				}
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+
+			start := time.Now()
+			results[i].Err = fn(ctx, name)
+			results[i].Duration = time.Since(start)
+		}(i, name)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// Failed compte les projets en échec
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Output sérialise les lignes écrites par des opérations simultanées
+type Output struct {
+	mu sync.Mutex
+}
+
+// Writer retourne un writer qui préfixe chaque ligne et l'écrit d'un bloc sur w.
+// Flush écrit la dernière ligne incomplète.
+func (o *Output) Writer(w io.Writer, prefix string) *LineWriter {
+	return &LineWriter{output: o, w: w, prefix: prefix}
+}
+
+// LineWriter découpe la sortie en lignes préfixées (voir Output.Writer).
+// Les retours chariot (barres de progression de compose) sont traités comme des fins de ligne.
+type LineWriter struct {
+	output  *Output
+	w       io.Writer
+	prefix  string
+	mu      sync.Mutex
+	partial strings.Builder
+}
+
+// Write implémente io.Writer
+func (l *LineWriter) Write(data []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, b := range data {
+		if b != '\n' && b != '\r' {
+			l.partial.WriteByte(b)
+			continue
+		}
+		l.writeLine()
+	}
+	return len(data), nil
+}
+
+// Flush écrit la ligne en cours
+func (l *LineWriter) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writeLine()
+}
+
+func (l *LineWriter) writeLine() {
+	line := strings.TrimRight(l.partial.String(), " ")
+	l.partial.Reset()
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	l.output.mu.Lock()
+	defer l.output.mu.Unlock()
+	fmt.Fprintf(l.w, "%s %s\n", l.prefix, line)
+}
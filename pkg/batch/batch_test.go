@@ -0,0 +1,130 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunParallelism(t *testing.T) {
+	var running, peak int32
+	projects := []string{"a", "b", "c", "d", "e", "f"}
+	results := Run(context.Background(), projects, Options{Parallelism: 2}, func(ctx context.Context, project string) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if project == "c" {
+			return errors.New("échec")
+		}
+		return nil
+	})
+
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("%d opérations simultanées, attendu 2 au plus", got)
+	}
+	for i, result := range results {
+		if result.Project != projects[i] {
+			t.Errorf("résultat %d = %s, attendu l'ordre des projets", i, result.Project)
+		}
+	}
+	if Failed(results) != 1 || results[2].Err == nil {
+		t.Errorf("échecs = %d, attendu c seul", Failed(results))
+	}
+}
+
+func TestRunAfter(t *testing.T) {
+	deps := map[string][]string{
+		"api":  {"db"},
+		"app":  {"api"},
+		"blog": {"cache"},
+		// web est placé avant worker : il ne l'attend pas (pas d'interblocage)
+		"web": {"worker"},
+	}
+	var mu sync.Mutex
+	var order []string
+	projects := []string{"db", "api", "app", "cache", "blog", "web", "worker"}
+	results := Run(context.Background(), projects, Options{
+		Parallelism: len(projects),
+		After:       func(project string) []string { return deps[project] },
+	}, func(ctx context.Context, project string) error {
+		mu.Lock()
+		order = append(order, project)
+		mu.Unlock()
+		if project == "cache" {
+			return errors.New("échec")
+		}
+		return nil
+	})
+
+	position := make(map[string]int)
+	for i, project := range order {
+		position[project] = i
+	}
+	if position["db"] > position["api"] || position["api"] > position["app"] {
+		t.Errorf("ordre = %q, attendu db puis api puis app", order)
+	}
+	if _, started := position["blog"]; started {
+		t.Error("blog lancé malgré l'échec de cache")
+	}
+	if err := results[4].Err; err == nil || !strings.Contains(err.Error(), "cache en échec") {
+		t.Errorf("blog : erreur = %v", err)
+	}
+	if Failed(results) != 2 {
+		t.Errorf("échecs = %d, attendu cache et blog", Failed(results))
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Le premier projet lancé annule le lot en gardant sa place : l'autre
+	// n'est jamais lancé
+	var calls int32
+	results := Run(ctx, []string{"a", "b"}, Options{Parallelism: 1}, func(ctx context.Context, project string) error {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("%d projets lancés, attendu 1", got)
+	}
+	if Failed(results) != 1 {
+		t.Fatalf("échecs = %d, attendu 1", Failed(results))
+	}
+	for _, result := range results {
+		if result.Err != nil && !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("%s : erreur = %v", result.Project, result.Err)
+		}
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var out strings.Builder
+	var output Output
+	web := output.Writer(&out, "[web]")
+	api := output.Writer(&out, "[api]")
+
+	web.Write([]byte("Pulling app\rPulled app   \n\n"))
+	api.Write([]byte("Starting"))
+	web.Write([]byte("Started\n"))
+	api.Write([]byte(" db\n  \nlast"))
+	api.Flush()
+	web.Flush()
+
+	want := "[web] Pulling app\n[web] Pulled app\n[web] Started\n[api] Starting db\n[api] last\n"
+	if out.String() != want {
+		t.Errorf("sortie = %q, attendu %q", out.String(), want)
+	}
+}
@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// detachDelay est le temps laissé à l'opener pour signaler une erreur ;
+// au-delà, il est laissé en arrière-plan (navigateur attaché à l'opener)
+const detachDelay = 2 * time.Second
+
+// Command retourne la commande qui ouvre target : l'opener configuré
+// (ex: "firefox --new-tab"), sinon open sur macOS, xdg-open ailleurs.
+// L'URL est ajoutée en dernier argument.
+func Command(opener string, target string) (*exec.Cmd, error) {
+	args := strings.Fields(opener)
+	if len(args) == 0 {
+		switch runtime.GOOS {
+		case "darwin":
+			args = []string{"open"}
+		case "windows":
+			args = []string{"rundll32", "url.dll,FileProtocolHandler"}
+		default:
+			args = []string{"xdg-open"}
+		}
+	}
+
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("opener '%s' introuvable (opener dans projects.yml)", args[0])
+	}
+	return exec.Command(args[0], append(args[1:], target)...), nil
+}
+
+// Open ouvre target dans le navigateur sans attendre sa fermeture.
+// La sortie de l'opener est ignorée (pas d'écriture sur le terminal).
+func Open(opener string, target string) error {
+	cmd, err := Command(opener, target)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("impossible de lancer %s: %w", cmd.Args[0], err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%s n'a pas pu ouvrir %s (code %d)", cmd.Args[0], target, exitErr.ExitCode())
+		}
+		return err
+	case <-time.After(detachDelay):
+		return nil
+	}
+}
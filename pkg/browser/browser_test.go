@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// withOpeners remplace le PATH par un dossier contenant les scripts donnés
+func withOpeners(t *testing.T, scripts map[string]string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts shell indisponibles")
+	}
+	dir := t.TempDir()
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestCommand(t *testing.T) {
+	withOpeners(t, map[string]string{"xdg-open": "exit 0", "open": "exit 0", "firefox": "exit 0"})
+
+	cmd, err := Command("firefox --new-tab", "http://localhost:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"firefox", "--new-tab", "http://localhost:8080"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("commande = %q, attendu %q", cmd.Args, want)
+	}
+
+	cmd, err = Command("  ", "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		want = "open"
+	}
+	if cmd.Args[0] != want || cmd.Args[len(cmd.Args)-1] != "http://localhost" {
+		t.Errorf("commande par défaut = %q, attendu %s", cmd.Args, want)
+	}
+
+	if _, err := Command("chromium", "http://localhost"); err == nil || !strings.Contains(err.Error(), "'chromium' introuvable") {
+		t.Errorf("opener absent : erreur = %v", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	withOpeners(t, map[string]string{"ok": "exit 0", "fails": "exit 3"})
+
+	if err := Open("ok", "http://localhost"); err != nil {
+		t.Errorf("opener réussi : %v", err)
+	}
+	if err := Open("fails", "http://localhost"); err == nil || !strings.Contains(err.Error(), "code 3") {
+		t.Errorf("opener en échec : erreur = %v", err)
+	}
+}
@@ -10,21 +10,121 @@ import (
 
 // ServiceConfig contient la config d'un service
 type ServiceConfig struct {
-	Name        string `yaml:"name"`
+	Name string `yaml:"name"`
+	// HealthCheck : URL http(s)://, adresse tcp://hôte:port ou commande
+	// exécutée dans le container (voir health.Parse)
 	HealthCheck string `yaml:"health_check,omitempty"`
+	// Scheme force le protocole des URLs du service (http, https, ou tcp
+	// pour lister ses ports comme hôte:port) ; déduit du port si vide
+	Scheme string `yaml:"scheme,omitempty"`
+	// Path est ajouté aux URLs du service (ex: /admin)
+	Path string `yaml:"path,omitempty"`
+	// Hostname remplace l'hôte des URLs (ex: app.localhost derrière Traefik)
+	Hostname string `yaml:"hostname,omitempty"`
 }
 
 // ProjectConfig contient la config d'un projet
 type ProjectConfig struct {
-	Path     string           `yaml:"path"`
-	Services []ServiceConfig  `yaml:"services,omitempty"`
-	Env      map[string]string `yaml:"env,omitempty"`
+	Path     string          `yaml:"path"`
+	Services []ServiceConfig `yaml:"services,omitempty"`
+	// Env est injecté dans l'environnement de chaque commande compose
+	Env map[string]string `yaml:"env,omitempty"`
+	// ComposeFiles liste les fichiers compose dans l'ordre d'application
+	// (relatifs au dossier du projet). Vide: docker-compose.yml + override détecté.
+	ComposeFiles []string `yaml:"compose_files,omitempty"`
+	// Profiles sont les profils compose activés par défaut
+	Profiles []string `yaml:"profiles,omitempty"`
+	// EnvFiles sont passés à compose via --env-file (ex: .env.local)
+	EnvFiles []string `yaml:"env_files,omitempty"`
+	// Start contient le mode de démarrage par défaut du projet
+	Start StartConfig `yaml:"start,omitempty"`
+	// DefaultService est le service utilisé par shell quand aucun n'est précisé
+	DefaultService string `yaml:"default_service,omitempty"`
+	// Shell force le shell ouvert par la commande shell (ex: /bin/zsh)
+	Shell string `yaml:"shell,omitempty"`
+	// Tasks sont les commandes ponctuelles nommées (compose run --rm)
+	Tasks map[string]TaskConfig `yaml:"tasks,omitempty"`
+	// Backup contient les volumes sauvegardés et la rétention des archives
+	Backup BackupConfig `yaml:"backup,omitempty"`
+	// DependsOn liste les projets démarrés avant celui-ci (ex: infra)
+	DependsOn []string `yaml:"depends_on,omitempty"`
+	// URL est l'URL principale ouverte par open (sinon déduite des ports publiés)
+	URL string `yaml:"url,omitempty"`
+}
+
+// BackupConfig contient les réglages de sauvegarde des volumes d'un projet
+type BackupConfig struct {
+	// Volumes sauvegardés par défaut (noms compose, ex: db) ; tous si vide
+	Volumes []string `yaml:"volumes,omitempty"`
+	// Keep est le nombre d'archives conservées par volume (0 = illimité)
+	Keep int `yaml:"keep,omitempty"`
+	// MaxAge supprime les archives plus anciennes (ex: "720h", "30d")
+	MaxAge string `yaml:"max_age,omitempty"`
+}
+
+// TaskConfig décrit une commande ponctuelle exécutée via compose run
+type TaskConfig struct {
+	Description string            `yaml:"description,omitempty"`
+	Service     string            `yaml:"service"`
+	Command     CommandLine       `yaml:"command"`
+	Env         map[string]string `yaml:"env,omitempty"`
+	Workdir     string            `yaml:"workdir,omitempty"`
+}
+
+// CommandLine accepte une liste d'arguments ou une chaîne.
+// Une chaîne est exécutée par sh -c (pour permettre &&, pipes, etc.).
+type CommandLine []string
+
+// UnmarshalYAML décode une commande sous forme de chaîne ou de liste
+func (c *CommandLine) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = CommandLine{"sh", "-c", value.Value}
+		return nil
+	}
+
+	var args []string
+	if err := value.Decode(&args); err != nil {
+		return fmt.Errorf("commande invalide (chaîne ou liste attendue): %w", err)
+	}
+	*c = args
+	return nil
+}
+
+// StartConfig contient les options de démarrage par défaut d'un projet
+type StartConfig struct {
+	NoBuild       bool     `yaml:"no_build,omitempty"`
+	Pull          bool     `yaml:"pull,omitempty"`
+	NoCache       bool     `yaml:"no_cache,omitempty"`
+	ForceRecreate bool     `yaml:"force_recreate,omitempty"`
+	Services      []string `yaml:"services,omitempty"`
+	// IgnorePortConflicts démarre malgré des ports déjà utilisés (avertissement)
+	IgnorePortConflicts bool `yaml:"ignore_port_conflicts,omitempty"`
+	// Wait attend que les services soient opérationnels (healthcheck Docker ou health_check)
+	Wait        bool   `yaml:"wait,omitempty"`
+	WaitTimeout string `yaml:"wait_timeout,omitempty"` // ex: "3m" (2m par défaut)
+}
+
+// TimeoutsConfig contient les timeouts par opération (ex: "30s", "20m")
+type TimeoutsConfig struct {
+	Query  string `yaml:"query,omitempty"`
+	Build  string `yaml:"build,omitempty"`
+	Up     string `yaml:"up,omitempty"`
+	Down   string `yaml:"down,omitempty"`
+	Daemon string `yaml:"daemon,omitempty"`
 }
 
 // Config contient la configuration globale
 type Config struct {
-	Root     string                     `yaml:"root,omitempty"`
-	Projects map[string]ProjectConfig   `yaml:"projects"`
+	Root     string                   `yaml:"root,omitempty"`
+	Compose  string                   `yaml:"compose,omitempty"` // auto, v1 ou v2
+	Timeouts TimeoutsConfig           `yaml:"timeouts,omitempty"`
+	Projects map[string]ProjectConfig `yaml:"projects"`
+	// Groups nomme des ensembles de projets gérés ensemble (start/stop/restart/status)
+	Groups map[string][]string `yaml:"groups,omitempty"`
+	// Parallelism est le nombre d'opérations simultanées sur un groupe (4 par défaut)
+	Parallelism int `yaml:"parallelism,omitempty"`
+	// Opener est la commande qui ouvre les URLs (défaut: open sur macOS, xdg-open ailleurs)
+	Opener string `yaml:"opener,omitempty"`
 }
 
 // EnsureDefaultConfig crée le fichier de config par défaut s'il n'existe pas
@@ -103,6 +203,27 @@ func SaveConfig(cfg *Config) error {
 	return nil
 }
 
+// BackupsDir retourne le répertoire des sauvegardes de volumes
+func BackupsDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".docker-manager", "backups")
+}
+
+// Group retourne les projets d'un groupe (noms normalisés, sans doublon)
+func (c *Config) Group(name string) ([]string, bool) {
+	members, ok := c.Groups[name]
+	if !ok {
+		return nil, false
+	}
+
+	var projects []string
+	for _, member := range members {
+		if project := ProjectName(member); project != "" && !contains(projects, project) {
+			projects = append(projects, project)
+		}
+	}
+	return projects, true
+}
+
 // GetProjectConfig retourne la configuration d'un projet spécifique
 func (c *Config) GetProjectConfig(projectName string) ProjectConfig {
 	if cfg, exists := c.Projects[projectName]; exists {
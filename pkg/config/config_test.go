@@ -0,0 +1,51 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCommandLineUnmarshal(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want CommandLine
+	}{
+		{`command: php artisan migrate && php artisan cache:clear`, CommandLine{"sh", "-c", "php artisan migrate && php artisan cache:clear"}},
+		{`command: [npm, run, build]`, CommandLine{"npm", "run", "build"}},
+	}
+	for _, tt := range tests {
+		var task TaskConfig
+		if err := yaml.Unmarshal([]byte(tt.yaml), &task); err != nil {
+			t.Fatalf("%s : %v", tt.yaml, err)
+		}
+		if !reflect.DeepEqual(task.Command, tt.want) {
+			t.Errorf("%s : commande = %q, attendu %q", tt.yaml, task.Command, tt.want)
+		}
+	}
+
+	var task TaskConfig
+	if err := yaml.Unmarshal([]byte("command: {run: build}"), &task); err == nil {
+		t.Error("commande en map : erreur attendue")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var cfg Config
+	content := `
+groups:
+  checkout: [docker-Shop, api, shop, " payment ", ""]
+`
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	members, ok := cfg.Group("checkout")
+	if want := []string{"shop", "api", "payment"}; !ok || !reflect.DeepEqual(members, want) {
+		t.Errorf("groupe = %q, %v, attendu %q", members, ok, want)
+	}
+	if _, ok := cfg.Group("inconnu"); ok {
+		t.Error("groupe inconnu trouvé")
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError signale un cycle dans les dépendances entre projets
+type CycleError struct {
+	Cycle []string // ex: [a b a]
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("cycle de dépendances : %s", strings.Join(e.Cycle, " → "))
+}
+
+// ProjectName normalise un nom de projet de depends_on ("docker-infra" → "infra")
+func ProjectName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "docker-"))
+}
+
+// Dependencies retourne les dépendances directes déclarées par un projet
+func (c *Config) Dependencies(projectName string) []string {
+	var deps []string
+	for _, dep := range c.GetProjectConfig(projectName).DependsOn {
+		if name := ProjectName(dep); name != "" && !contains(deps, name) {
+			deps = append(deps, name)
+		}
+	}
+	return deps
+}
+
+// StartOrder retourne les projets à démarrer, dépendances d'abord (ordre
+// topologique), en terminant par les projets demandés
+func (c *Config) StartOrder(projectNames ...string) ([]string, error) {
+	var order []string
+	done := make(map[string]bool)
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		if done[name] {
+			return nil
+		}
+		for i, visiting := range path {
+			if visiting == name {
+				cycle := append(append([]string{}, path[i:]...), name)
+				return &CycleError{Cycle: cycle}
+			}
+		}
+
+		path = append(path, name)
+		for _, dep := range c.Dependencies(name) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		done[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range projectNames {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Dependents retourne les projets qui dépendent, directement ou non, de ce projet (triés)
+func (c *Config) Dependents(projectName string) []string {
+	var dependents []string
+	queue := []string{projectName}
+	seen := map[string]bool{projectName: true}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for name := range c.Projects {
+			if seen[name] || !contains(c.Dependencies(name), current) {
+				continue
+			}
+			seen[name] = true
+			dependents = append(dependents, name)
+			queue = append(queue, name)
+		}
+	}
+
+	sort.Strings(dependents)
+	return dependents
+}
+
+// CheckDependencies vérifie que le graphe de dépendances ne contient pas de cycle
+func (c *Config) CheckDependencies() error {
+	names := make([]string, 0, len(c.Projects))
+	for name := range c.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, err := c.StartOrder(names...)
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
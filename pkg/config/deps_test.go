@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// depsConfig construit une configuration à partir des depends_on de chaque projet
+func depsConfig(deps map[string][]string) *Config {
+	cfg := &Config{Projects: make(map[string]ProjectConfig)}
+	for name, dependsOn := range deps {
+		cfg.Projects[name] = ProjectConfig{DependsOn: dependsOn}
+	}
+	return cfg
+}
+
+func TestDependencies(t *testing.T) {
+	cfg := depsConfig(map[string][]string{
+		"app": {"docker-Infra", " infra ", "docker-db", ""},
+	})
+	if got, want := cfg.Dependencies("app"), []string{"infra", "db"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dépendances = %q, attendu %q", got, want)
+	}
+	if got := cfg.Dependencies("inconnu"); got != nil {
+		t.Errorf("projet sans configuration : %q", got)
+	}
+}
+
+func TestStartOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		deps     map[string][]string
+		projects []string
+		want     []string
+	}{
+		{"chaîne", map[string][]string{"app": {"api"}, "api": {"db"}}, []string{"app"}, []string{"db", "api", "app"}},
+		{
+			"losange",
+			map[string][]string{"app": {"front", "back"}, "front": {"db"}, "back": {"docker-db"}},
+			[]string{"app"},
+			[]string{"db", "front", "back", "app"},
+		},
+		{"plusieurs projets", map[string][]string{"app": {"db"}, "blog": {"db"}}, []string{"blog", "app"}, []string{"db", "blog", "app"}},
+		{"sans dépendance", nil, []string{"web"}, []string{"web"}},
+	}
+	for _, tt := range tests {
+		got, err := depsConfig(tt.deps).StartOrder(tt.projects...)
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s : ordre = %q, attendu %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStartOrderCycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		deps      map[string][]string
+		project   string
+		wantCycle []string
+	}{
+		{"cycle", map[string][]string{"web": {"a"}, "a": {"b"}, "b": {"c"}, "c": {"a"}}, "web", []string{"a", "b", "c", "a"}},
+		{"auto-dépendance", map[string][]string{"x": {"docker-x"}}, "x", []string{"x", "x"}},
+	}
+	for _, tt := range tests {
+		cfg := depsConfig(tt.deps)
+		_, err := cfg.StartOrder(tt.project)
+		var cycleErr *CycleError
+		if !errors.As(err, &cycleErr) {
+			t.Errorf("%s : erreur = %v, attendu un CycleError", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(cycleErr.Cycle, tt.wantCycle) {
+			t.Errorf("%s : cycle = %q, attendu %q", tt.name, cycleErr.Cycle, tt.wantCycle)
+		}
+		if err := cfg.CheckDependencies(); !errors.As(err, &cycleErr) {
+			t.Errorf("%s : CheckDependencies = %v, attendu un CycleError", tt.name, err)
+		}
+	}
+
+	if err := depsConfig(map[string][]string{"app": {"db"}}).CheckDependencies(); err != nil {
+		t.Errorf("graphe sans cycle : %v", err)
+	}
+}
+
+func TestDependents(t *testing.T) {
+	cfg := depsConfig(map[string][]string{
+		"app":   {"front", "back"},
+		"front": {"db"},
+		"back":  {"docker-DB"},
+		"blog":  {"cache"},
+	})
+	tests := []struct {
+		project string
+		want    []string
+	}{
+		{"db", []string{"app", "back", "front"}},
+		{"front", []string{"app"}},
+		{"app", nil},
+	}
+	for _, tt := range tests {
+		if got := cfg.Dependents(tt.project); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Dependents(%q) = %q, attendu %q", tt.project, got, tt.want)
+		}
+	}
+
+	// Un cycle ne fait pas boucler le parcours
+	cycle := depsConfig(map[string][]string{"a": {"b"}, "b": {"a"}})
+	if got, want := cycle.Dependents("a"), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents en cycle = %q, attendu %q", got, want)
+	}
+}
@@ -10,9 +10,15 @@ import (
 	"github.com/phil/docker-manager/pkg/project"
 )
 
+// overrideFiles sont les fichiers d'override détectés automatiquement,
+// par ordre de préférence
+var overrideFiles = []string{"docker-compose.override.yml", "docker-compose.override.yaml"}
+
 // Discoverer détecte automatiquement les projets Docker
 type Discoverer struct {
 	SearchPath string
+	// Config optionnelle : fichiers compose, profils et environnement de chaque projet
+	Config *config.Config
 }
 
 // NewDiscoverer crée un nouveau découvreur
@@ -43,7 +49,7 @@ func (d *Discoverer) Discover() ([]project.Project, error) {
 		}
 
 		projectPath := filepath.Join(d.SearchPath, entry.Name())
-		composePath := filepath.Join(projectPath, "docker-compose.yml")
+		composePath := filepath.Join(projectPath, project.DefaultComposeFile)
 
 		// Vérifier que docker-compose.yml existe
 		if _, err := os.Stat(composePath); os.IsNotExist(err) {
@@ -55,27 +61,81 @@ func (d *Discoverer) Discover() ([]project.Project, error) {
 		projectName := strings.ToLower(strings.TrimPrefix(entry.Name(), "docker-"))
 
 		p := project.Project{
-			Name:        projectName,
-			Path:        projectPath,
-			ComposePath: composePath,
-			Services:    []project.Service{},
-			Running:     false,
+			Name:         projectName,
+			Path:         projectPath,
+			ComposePath:  composePath,
+			ComposeFiles: d.composeFiles(projectName, projectPath),
+			Services:     []project.Service{},
+			Running:      false,
 		}
 
+		d.applyConfig(&p)
 		projects = append(projects, p)
 	}
 
 	return projects, nil
 }
 
+// composeFiles retourne les fichiers configurés pour le projet, sinon
+// docker-compose.yml suivi de l'override standard s'il existe
+func (d *Discoverer) composeFiles(projectName string, projectPath string) []string {
+	if d.Config != nil {
+		if files := d.Config.GetProjectConfig(projectName).ComposeFiles; len(files) > 0 {
+			return append([]string{}, files...)
+		}
+	}
+
+	files := []string{project.DefaultComposeFile}
+	for _, override := range overrideFiles {
+		if _, err := os.Stat(filepath.Join(projectPath, override)); err == nil {
+			files = append(files, override)
+			break
+		}
+	}
+	return files
+}
+
+// applyConfig reporte les réglages du projet (profils, environnement, URLs)
+func (d *Discoverer) applyConfig(p *project.Project) {
+	if d.Config == nil {
+		return
+	}
+
+	cfg := d.Config.GetProjectConfig(p.Name)
+	p.Profiles = append([]string(nil), cfg.Profiles...)
+	p.EnvFiles = append([]string(nil), cfg.EnvFiles...)
+	p.URL = cfg.URL
+	if len(cfg.Env) > 0 {
+		p.Env = make(map[string]string, len(cfg.Env))
+		for key, value := range cfg.Env {
+			p.Env[key] = value
+		}
+	}
+	for _, service := range cfg.Services {
+		if service.Scheme == "" && service.Path == "" && service.Hostname == "" {
+			continue
+		}
+		if p.URLSettings == nil {
+			p.URLSettings = make(map[string]project.URLSettings)
+		}
+		p.URLSettings[service.Name] = project.URLSettings{
+			Scheme:   strings.ToLower(service.Scheme),
+			Path:     service.Path,
+			Hostname: service.Hostname,
+		}
+	}
+}
+
 // DiscoverInDefaultPath découvre les projets dans le chemin Docker par défaut
 func DiscoverInDefaultPath() ([]project.Project, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		cfg = nil
+	}
+
 	rootDir := os.Getenv("DOCKER_MANAGER_ROOT")
-	if rootDir == "" {
-		cfg, err := config.LoadConfig()
-		if err == nil && cfg.Root != "" {
-			rootDir = cfg.Root
-		}
+	if rootDir == "" && cfg != nil && cfg.Root != "" {
+		rootDir = cfg.Root
 	}
 	if rootDir == "" {
 		rootDir = defaultRootDir()
@@ -86,6 +146,7 @@ func DiscoverInDefaultPath() ([]project.Project, error) {
 	}
 
 	discoverer := NewDiscoverer(rootDir)
+	discoverer.Config = cfg
 	return discoverer.Discover()
 }
 
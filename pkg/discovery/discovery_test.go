@@ -0,0 +1,64 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/config"
+)
+
+// writeFiles crée les fichiers (chemins relatifs à root) et leurs dossiers
+func writeFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("services: {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscoverComposeFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"docker-web/docker-compose.yml",
+		"docker-web/docker-compose.override.yml",
+		"docker-web/docker-compose.override.yaml",
+		"docker-Blog/docker-compose.yml",
+		"docker-blog-yaml/docker-compose.yml",
+		"docker-blog-yaml/docker-compose.override.yaml",
+		"docker-shop/docker-compose.yml",
+		"docker-shop/docker-compose.override.yml",
+		"docker-empty/README.md",
+		"other/docker-compose.yml",
+	)
+
+	discoverer := NewDiscoverer(root)
+	discoverer.Config = &config.Config{Projects: map[string]config.ProjectConfig{
+		// Liste explicite : l'override n'est pas ajouté, l'ordre est conservé
+		"shop": {ComposeFiles: []string{"docker-compose.yml", "docker-compose.prod.yml"}},
+	}}
+	projects, err := discoverer.Discover()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string][]string)
+	for _, p := range projects {
+		got[p.Name] = p.ComposeFiles
+	}
+	want := map[string][]string{
+		"web":       {"docker-compose.yml", "docker-compose.override.yml"},
+		"blog":      {"docker-compose.yml"},
+		"blog-yaml": {"docker-compose.yml", "docker-compose.override.yaml"},
+		"shop":      {"docker-compose.yml", "docker-compose.prod.yml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fichiers compose = %v, attendu %v", got, want)
+	}
+}
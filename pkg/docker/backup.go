@@ -0,0 +1,459 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/config"
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// BackupImage est l'image du container utilisé pour lire et écrire les volumes
+var BackupImage = "alpine:3"
+
+// backupTimeFormat horodate les archives (ex: db_20240131-184502.tar.gz)
+const backupTimeFormat = "20060102-150405"
+
+// ProjectVolume est un volume nommé d'un projet
+type ProjectVolume struct {
+	Name  string // nom Docker (ex: pbwww_db)
+	Short string // nom dans le compose (ex: db)
+}
+
+// Backup est une archive de volume
+type Backup struct {
+	Project string
+	Volume  string // nom compose du volume
+	Time    time.Time
+	Path    string
+	Size    int64
+	seq     int // numéro de l'archive dans la même seconde (1 pour la première)
+}
+
+// ProjectVolumes liste les volumes nommés créés par compose pour le projet
+func (m *Manager) ProjectVolumes(ctx context.Context, p *project.Project) ([]ProjectVolume, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	filter := fmt.Sprintf("%s=%s", LabelProject, composeProjectName(p.Name))
+	var volumes []ProjectVolume
+	if m.Engine != nil {
+		if list, err := m.Engine.ListVolumes(ctx, map[string][]string{"label": {filter}}); err == nil {
+			for _, volume := range list {
+				volumes = append(volumes, ProjectVolume{Name: volume.Name, Short: volumeShortName(volume.Labels[LabelVolume], volume.Name)})
+			}
+			sortVolumes(volumes)
+			return volumes, nil
+		}
+	}
+
+	output, err := dockerOutput(ctx, "volume", "ls", "--filter", "label="+filter,
+		"--format", fmt.Sprintf("{{.Name}}\t{{.Label \"%s\"}}", LabelVolume))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des volumes: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, short, _ := strings.Cut(line, "\t")
+		if name != "" {
+			volumes = append(volumes, ProjectVolume{Name: name, Short: volumeShortName(short, name)})
+		}
+	}
+	sortVolumes(volumes)
+	return volumes, nil
+}
+
+// VolumeNotFoundError signale qu'aucun volume du projet ne porte ce nom
+type VolumeNotFoundError struct {
+	Project   string
+	Volume    string
+	Available []string // noms compose des volumes existants
+}
+
+func (e *VolumeNotFoundError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("le projet %s n'a aucun volume nommé (a-t-il déjà été démarré ?)", e.Project)
+	}
+	return fmt.Sprintf("volume '%s' introuvable dans %s (volumes: %s)", e.Volume, e.Project, strings.Join(e.Available, ", "))
+}
+
+// FindVolume retrouve un volume du projet par son nom compose ou Docker.
+// Retourne un *VolumeNotFoundError si le volume n'existe pas.
+func (m *Manager) FindVolume(ctx context.Context, p *project.Project, name string) (ProjectVolume, error) {
+	volumes, err := m.ProjectVolumes(ctx, p)
+	if err != nil {
+		return ProjectVolume{}, err
+	}
+
+	names := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		if volume.Short == name || volume.Name == name {
+			return volume, nil
+		}
+		names = append(names, volume.Short)
+	}
+	return ProjectVolume{}, &VolumeNotFoundError{Project: p.Name, Volume: name, Available: names}
+}
+
+// ComposeVolume retourne le volume tel que compose le crée pour le projet
+// (<projet normalisé>_<volume>), qu'il existe ou non
+func ComposeVolume(p *project.Project, name string) ProjectVolume {
+	return ProjectVolume{Name: composeProjectName(p.Name) + "_" + name, Short: name}
+}
+
+// composeProjectName normalise un nom de projet comme compose : minuscules,
+// uniquement [a-z0-9_-], sans _ ni - en tête
+func composeProjectName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimLeft(b.String(), "_-")
+}
+
+// volumeShortName retourne le nom compose, ou le nom Docker à défaut
+func volumeShortName(label, name string) string {
+	if label != "" {
+		return label
+	}
+	return name
+}
+
+func sortVolumes(volumes []ProjectVolume) {
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Short < volumes[j].Short
+	})
+}
+
+// BackupVolume archive un volume dans dir/<projet>/<volume>_<date>.tar.gz
+// (<volume>_<date>-<n>.tar.gz si une archive existe déjà pour cette seconde).
+// Le volume est monté en lecture seule dans un container BackupImage et
+// l'archive est écrite par docker-manager (fichier appartenant à l'utilisateur).
+func (m *Manager) BackupVolume(ctx context.Context, p *project.Project, volume ProjectVolume, dir string) (Backup, error) {
+	backup := Backup{Project: p.Name, Volume: volume.Short, Time: time.Now().Truncate(time.Second)}
+	if err := checkBackupProject(p.Name); err != nil {
+		return backup, err
+	}
+	projectDir := filepath.Join(dir, p.Name)
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return backup, fmt.Errorf("erreur lors de la création du répertoire: %w", err)
+	}
+
+	// Écrire dans un fichier temporaire : une archive interrompue n'est jamais listée
+	file, err := createBackupFile(&backup, projectDir)
+	if err != nil {
+		return backup, fmt.Errorf("erreur lors de la création de l'archive: %w", err)
+	}
+	partial := file.Name()
+
+	if users, err := m.volumeUsers(ctx, volume); err == nil && len(users) > 0 {
+		m.report(p, "backup", false, "⚠️  %s est utilisé par %s : l'archive peut être incohérente si des écritures ont lieu", volume.Short, strings.Join(users, ", "))
+	}
+	m.report(p, "backup", false, "💾 Sauvegarde du volume %s...", volume.Short)
+
+	err = m.runHelper(ctx, "backup", nil, file,
+		"-v", volume.Name+":/volume:ro", BackupImage, "tar", "czf", "-", "-C", "/volume", ".")
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partial)
+		return backup, fmt.Errorf("sauvegarde de %s échouée: %w", volume.Short, err)
+	}
+	if err := os.Rename(partial, backup.Path); err != nil {
+		os.Remove(partial)
+		return backup, err
+	}
+
+	if info, err := os.Stat(backup.Path); err == nil {
+		backup.Size = info.Size()
+	}
+	m.report(p, "backup", true, "✅ %s sauvegardé : %s (%s)", volume.Short, backup.Path, FormatBytes(uint64(backup.Size)))
+	return backup, nil
+}
+
+// createBackupFile réserve le fichier temporaire de l'archive et renseigne
+// backup.Path. Un numéro est ajouté tant qu'une archive (ou une sauvegarde en
+// cours) porte déjà le même nom, pour ne jamais en écraser une.
+func createBackupFile(backup *Backup, projectDir string) (*os.File, error) {
+	for seq := 1; ; seq++ {
+		name := fmt.Sprintf("%s_%s", backup.Volume, backup.Time.Format(backupTimeFormat))
+		if seq > 1 {
+			name += fmt.Sprintf("-%d", seq)
+		}
+		path := filepath.Join(projectDir, name+".tar.gz")
+		if _, err := os.Lstat(path); err == nil {
+			continue
+		}
+
+		file, err := os.OpenFile(path+".partial", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		backup.Path, backup.seq = path, seq
+		return file, nil
+	}
+}
+
+// checkBackupProject refuse un nom de projet qui sortirait du répertoire des sauvegardes
+func checkBackupProject(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("nom de projet invalide pour les sauvegardes: %q", name)
+	}
+	return nil
+}
+
+// RestoreVolume remplace le contenu d'un volume par une archive.
+// Le volume ne doit être utilisé par aucun container démarré ; il est créé
+// (avec les labels compose) s'il n'existe plus.
+func (m *Manager) RestoreVolume(ctx context.Context, p *project.Project, volume ProjectVolume, backup Backup) error {
+	users, err := m.volumeUsers(ctx, volume)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return fmt.Errorf("le volume %s est utilisé par %s : arrêtez le projet avant la restauration (docker-manager stop %s --keep)",
+			volume.Short, strings.Join(users, ", "), p.Name)
+	}
+
+	file, err := os.Open(backup.Path)
+	if err != nil {
+		return fmt.Errorf("archive illisible: %w", err)
+	}
+	defer file.Close()
+
+	if err := m.ensureVolume(ctx, p, volume); err != nil {
+		return err
+	}
+
+	m.report(p, "restore", false, "♻️  Restauration de %s depuis %s...", volume.Short, filepath.Base(backup.Path))
+	err = m.runHelper(ctx, "restore", file, io.Discard,
+		"-i", "-v", volume.Name+":/volume", BackupImage,
+		"sh", "-c", "find /volume -mindepth 1 -delete && tar xzf - -C /volume")
+	if err != nil {
+		return fmt.Errorf("restauration de %s échouée: %w", volume.Short, err)
+	}
+
+	m.report(p, "restore", true, "✅ Volume %s restauré (%s)", volume.Short, backup.Time.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// ensureVolume crée le volume s'il a été supprimé, avec les labels attendus par compose
+func (m *Manager) ensureVolume(ctx context.Context, p *project.Project, volume ProjectVolume) error {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	if _, err := dockerOutput(ctx, "volume", "inspect", volume.Name); err == nil {
+		return nil
+	}
+	_, err := dockerOutput(ctx, "volume", "create",
+		"--label", LabelProject+"="+composeProjectName(p.Name),
+		"--label", LabelVolume+"="+volume.Short,
+		volume.Name)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la création du volume %s: %w", volume.Name, err)
+	}
+	return nil
+}
+
+// volumeUsers retourne les containers démarrés qui montent le volume
+func (m *Manager) volumeUsers(ctx context.Context, volume ProjectVolume) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	if m.Engine != nil {
+		if containers, err := m.Engine.ListContainers(ctx, false, map[string][]string{"volume": {volume.Name}}); err == nil {
+			var names []string
+			for _, container := range containers {
+				names = append(names, containerName(container.Names))
+			}
+			return names, nil
+		}
+	}
+
+	output, err := dockerOutput(ctx, "ps", "--filter", "volume="+volume.Name, "--format", "{{.Names}}")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des containers: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// runHelper lance un container éphémère (docker run --rm) relié à stdin/stdout.
+// L'archivage d'un gros volume peut être long : le timeout de build s'applique.
+func (m *Manager) runHelper(ctx context.Context, step string, stdin io.Reader, stdout io.Writer, args ...string) error {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Build)
+	defer cancel()
+
+	var stderr strings.Builder
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"run", "--rm"}, args...)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return &StepError{Step: step, Err: ctx.Err()}
+		}
+		return &CommandError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return nil
+}
+
+// ListBackups liste les archives d'un projet (tous si projectName est vide),
+// triées par projet, volume puis de la plus récente à la plus ancienne
+func ListBackups(dir string, projectName string) ([]Backup, error) {
+	projects := []string{projectName}
+	if projectName != "" {
+		if err := checkBackupProject(projectName); err != nil {
+			return nil, err
+		}
+	} else {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		projects = projects[:0]
+		for _, entry := range entries {
+			if entry.IsDir() {
+				projects = append(projects, entry.Name())
+			}
+		}
+	}
+
+	var backups []Backup
+	for _, name := range projects {
+		entries, err := os.ReadDir(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			backup, ok := parseBackupName(entry.Name())
+			if !ok {
+				continue
+			}
+			backup.Project = name
+			backup.Path = filepath.Join(dir, name, entry.Name())
+			if info, err := entry.Info(); err == nil {
+				backup.Size = info.Size()
+			}
+			backups = append(backups, backup)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		a, b := backups[i], backups[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Volume != b.Volume {
+			return a.Volume < b.Volume
+		}
+		if !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+		return a.seq > b.seq
+	})
+	return backups, nil
+}
+
+// parseBackupName lit "<volume>_<date>.tar.gz" ou "<volume>_<date>-<n>.tar.gz"
+func parseBackupName(fileName string) (Backup, bool) {
+	base, ok := strings.CutSuffix(fileName, ".tar.gz")
+	if !ok {
+		return Backup{}, false
+	}
+	i := strings.LastIndex(base, "_")
+	if i <= 0 {
+		return Backup{}, false
+	}
+
+	stamp, seq := base[i+1:], 1
+	if len(stamp) > len(backupTimeFormat) && stamp[len(backupTimeFormat)] == '-' {
+		n, err := strconv.Atoi(stamp[len(backupTimeFormat)+1:])
+		if err != nil || n < 2 {
+			return Backup{}, false
+		}
+		stamp, seq = stamp[:len(backupTimeFormat)], n
+	}
+	date, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+	if err != nil {
+		return Backup{}, false
+	}
+	return Backup{Volume: base[:i], Time: date, seq: seq}, true
+}
+
+// Retention définit les archives conservées pour chaque volume
+type Retention struct {
+	Keep   int           // nombre d'archives conservées (0 = illimité)
+	MaxAge time.Duration // âge maximum (0 = illimité)
+}
+
+// RetentionFromConfig convertit la section backup: d'un projet.
+// MaxAge accepte les durées Go ("720h") et les jours ("30d").
+func RetentionFromConfig(cfg config.BackupConfig) (Retention, error) {
+	retention := Retention{Keep: cfg.Keep}
+	if cfg.MaxAge == "" {
+		return retention, nil
+	}
+
+	if days, ok := strings.CutSuffix(cfg.MaxAge, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return retention, fmt.Errorf("max_age invalide (%q): %w", cfg.MaxAge, err)
+		}
+		retention.MaxAge = time.Duration(n) * 24 * time.Hour
+		return retention, nil
+	}
+
+	maxAge, err := time.ParseDuration(cfg.MaxAge)
+	if err != nil {
+		return retention, fmt.Errorf("max_age invalide (%q): %w", cfg.MaxAge, err)
+	}
+	retention.MaxAge = maxAge
+	return retention, nil
+}
+
+// PruneBackups supprime les archives hors rétention et retourne celles supprimées.
+// La plus récente archive de chaque volume est toujours conservée.
+func PruneBackups(backups []Backup, retention Retention, now time.Time) ([]Backup, error) {
+	var removed []Backup
+	kept := make(map[string]int)
+
+	// backups est trié du plus récent au plus ancien pour chaque volume
+	for _, backup := range backups {
+		key := backup.Project + "/" + backup.Volume
+		kept[key]++
+		if kept[key] == 1 {
+			continue
+		}
+
+		tooMany := retention.Keep > 0 && kept[key] > retention.Keep
+		tooOld := retention.MaxAge > 0 && now.Sub(backup.Time) > retention.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		if err := os.Remove(backup.Path); err != nil {
+			return removed, fmt.Errorf("erreur lors de la suppression de %s: %w", backup.Path, err)
+		}
+		removed = append(removed, backup)
+	}
+	return removed, nil
+}
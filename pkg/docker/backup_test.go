@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+func TestComposeVolume(t *testing.T) {
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"web", "web_data"},
+		{"My.Blog", "myblog_data"},
+		{"_shop-v2", "shop-v2_data"},
+	}
+	for _, tt := range tests {
+		volume := ComposeVolume(&project.Project{Name: tt.project}, "data")
+		if volume.Name != tt.want || volume.Short != "data" {
+			t.Errorf("ComposeVolume(%q) = %+v, attendu %s", tt.project, volume, tt.want)
+		}
+	}
+}
+
+func TestParseBackupName(t *testing.T) {
+	date := time.Date(2024, 1, 31, 18, 45, 2, 0, time.Local)
+	tests := []struct {
+		name   string
+		want   Backup
+		wantOK bool
+	}{
+		{"db_20240131-184502.tar.gz", Backup{Volume: "db", Time: date, seq: 1}, true},
+		{"app_data_20240131-184502.tar.gz", Backup{Volume: "app_data", Time: date, seq: 1}, true},
+		{"db_20240131-184502-3.tar.gz", Backup{Volume: "db", Time: date, seq: 3}, true},
+		{"db_20240131-184502-x.tar.gz", Backup{}, false},
+		{"db_20240131-184502.tar.gz.partial", Backup{}, false},
+		{"notes.txt", Backup{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseBackupName(tt.name)
+		if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseBackupName(%q) = %+v, %v, attendu %+v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCreateBackupFileNeverOverwrites(t *testing.T) {
+	dir := t.TempDir()
+	stamp := time.Date(2024, 1, 31, 18, 45, 2, 0, time.Local)
+
+	// Deux sauvegardes dans la même seconde : la seconde est numérotée
+	var paths []string
+	for i := 0; i < 3; i++ {
+		backup := Backup{Volume: "db", Time: stamp}
+		file, err := createBackupFile(&backup, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+		if i < 2 {
+			// Archive terminée
+			if err := os.Rename(file.Name(), backup.Path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		paths = append(paths, filepath.Base(backup.Path))
+	}
+	want := []string{"db_20240131-184502.tar.gz", "db_20240131-184502-2.tar.gz", "db_20240131-184502-3.tar.gz"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("archives = %q, attendu %q", paths, want)
+	}
+
+	// Une sauvegarde en cours (.partial) réserve aussi son nom
+	backup := Backup{Volume: "db", Time: stamp}
+	file, err := createBackupFile(&backup, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	if got := filepath.Base(backup.Path); got != "db_20240131-184502-4.tar.gz" {
+		t.Errorf("archive = %s, attendu le numéro 4", got)
+	}
+}
+
+func TestListBackups(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"web/db_20240130-100000.tar.gz",
+		"web/db_20240131-184502.tar.gz",
+		"web/db_20240131-184502-2.tar.gz",
+		"web/cache_20240131-090000.tar.gz",
+		"web/db_20240131-190000.tar.gz.partial",
+		"blog/data_20240101-000000.tar.gz",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(backups []Backup) []string {
+		var names []string
+		for _, backup := range backups {
+			names = append(names, backup.Project+"/"+filepath.Base(backup.Path))
+		}
+		return names
+	}
+
+	backups, err := ListBackups(dir, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"web/cache_20240131-090000.tar.gz",
+		"web/db_20240131-184502-2.tar.gz",
+		"web/db_20240131-184502.tar.gz",
+		"web/db_20240130-100000.tar.gz",
+	}
+	if got := names(backups); !reflect.DeepEqual(got, want) {
+		t.Errorf("sauvegardes = %q, attendu %q", got, want)
+	}
+
+	all, err := ListBackups(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 || all[0].Project != "blog" {
+		t.Errorf("toutes les sauvegardes = %q", names(all))
+	}
+
+	for _, name := range []string{"..", ".", "../web", "web/../blog", `web\..`} {
+		if _, err := ListBackups(filepath.Join(dir, "web"), name); err == nil {
+			t.Errorf("ListBackups(%q) : erreur attendue", name)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// CleanLevel est le niveau de nettoyage d'un projet ; chaque niveau inclut les précédents
+type CleanLevel int
+
+const (
+	// CleanOrphans supprime les containers des services retirés du compose
+	CleanOrphans CleanLevel = iota
+	// CleanImages supprime aussi les containers, réseaux et images construites du projet
+	CleanImages
+	// CleanVolumes supprime aussi les volumes nommés du projet (données perdues)
+	CleanVolumes
+	// CleanAll supprime aussi les images téléchargées utilisées uniquement par ce projet
+	CleanAll
+)
+
+var cleanLevelNames = []string{"orphans", "images", "volumes", "all"}
+
+// String retourne le nom du niveau (orphans, images, volumes, all)
+func (l CleanLevel) String() string {
+	if int(l) < len(cleanLevelNames) {
+		return cleanLevelNames[l]
+	}
+	return fmt.Sprintf("CleanLevel(%d)", int(l))
+}
+
+// ParseCleanLevel convertit un nom de niveau
+func ParseCleanLevel(value string) (CleanLevel, error) {
+	for i, name := range cleanLevelNames {
+		if strings.EqualFold(strings.TrimSpace(value), name) {
+			return CleanLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("niveau de nettoyage inconnu: %q (attendu: %s)", value, strings.Join(cleanLevelNames, ", "))
+}
+
+// CleanItem est une ressource Docker que le nettoyage supprimera
+type CleanItem struct {
+	ID      string
+	Name    string
+	Size    int64  // 0 si inconnue
+	Note    string // raison de la suppression (ex: "service retiré du compose")
+	Running bool   // container démarré : il sera arrêté de force
+}
+
+// CleanPlan liste exactement ce que Clean supprimera
+type CleanPlan struct {
+	Project    string
+	Level      CleanLevel
+	Containers []CleanItem
+	Networks   []CleanItem
+	Images     []CleanItem
+	Volumes    []CleanItem
+	// Kept liste les containers démarrés conservés (includeRunning faux) ;
+	// leurs images et les réseaux du projet sont alors conservés aussi
+	Kept []CleanItem
+}
+
+// RunningContainers retourne le nombre de containers démarrés que Clean arrêtera
+func (p *CleanPlan) RunningContainers() int {
+	count := 0
+	for _, item := range p.Containers {
+		if item.Running {
+			count++
+		}
+	}
+	return count
+}
+
+// Empty indique qu'il n'y a rien à supprimer
+func (p *CleanPlan) Empty() bool {
+	return len(p.Containers)+len(p.Networks)+len(p.Images)+len(p.Volumes) == 0
+}
+
+// Size retourne l'espace disque libéré (tailles connues uniquement)
+func (p *CleanPlan) Size() int64 {
+	var total int64
+	for _, items := range [][]CleanItem{p.Containers, p.Images, p.Volumes} {
+		for _, item := range items {
+			total += item.Size
+		}
+	}
+	return total
+}
+
+// PlanClean calcule ce que le nettoyage du projet supprimera, sans rien modifier.
+// Seules les ressources portant le label compose du projet (ou ses images
+// construites) sont retenues ; les images utilisées par d'autres containers
+// sont conservées. Les containers démarrés ne sont supprimés que si
+// includeRunning est vrai ; sinon ils sont listés dans Kept.
+func (m *Manager) PlanClean(ctx context.Context, p *project.Project, level CleanLevel, includeRunning bool) (*CleanPlan, error) {
+	services, err := m.allServices(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := m.systemDiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &CleanPlan{Project: p.Name, Level: level}
+
+	// Containers : orphelins, ou tous ceux du projet à partir du niveau images
+	removed := make(map[string]bool)
+	for _, container := range usage.Containers {
+		if container.Labels[LabelProject] != p.Name {
+			continue
+		}
+		service := container.Labels[LabelService]
+		note := ""
+		if !services[service] {
+			note = "service retiré du compose"
+		} else if level < CleanImages {
+			continue
+		}
+		item := CleanItem{
+			ID:      shortID(container.ID),
+			Name:    containerName(container.Names),
+			Size:    container.SizeRw,
+			Note:    note,
+			Running: container.Active(),
+		}
+		if item.Running {
+			item.Note = strings.TrimPrefix(note+", en cours d'exécution", ", ")
+			if !includeRunning {
+				plan.Kept = append(plan.Kept, item)
+				continue
+			}
+		}
+		plan.Containers = append(plan.Containers, item)
+		removed[container.ID] = true
+	}
+	if level < CleanImages {
+		return plan, nil
+	}
+
+	// Un réseau encore utilisé par un container conservé ne peut pas être supprimé
+	if len(plan.Kept) == 0 {
+		networks, err := m.projectNetworks(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		plan.Networks = networks
+	}
+
+	// Images encore utilisées par un container qui ne sera pas supprimé
+	inUse := make(map[string]bool)
+	usedByProject := make(map[string]bool)
+	for _, container := range usage.Containers {
+		if removed[container.ID] {
+			usedByProject[container.ImageID] = true
+			usedByProject[container.Image] = true
+			continue
+		}
+		inUse[container.ImageID] = true
+		inUse[container.Image] = true
+	}
+
+	for _, image := range usage.Images {
+		name := imageName(image)
+		if inUse[image.ID] || anyTag(image.RepoTags, inUse) {
+			continue
+		}
+
+		switch {
+		case image.Labels[LabelProject] == p.Name || builtByProject(image.RepoTags, p.Name, services):
+			plan.Images = append(plan.Images, CleanItem{ID: shortImageID(image.ID), Name: name, Size: image.UniqueSize(), Note: "construite"})
+		case level >= CleanAll && (usedByProject[image.ID] || anyTag(image.RepoTags, usedByProject)):
+			plan.Images = append(plan.Images, CleanItem{ID: shortImageID(image.ID), Name: name, Size: image.UniqueSize(), Note: "téléchargée"})
+		}
+	}
+
+	if level >= CleanVolumes {
+		for _, volume := range usage.Volumes {
+			if volume.Labels[LabelProject] == p.Name {
+				plan.Volumes = append(plan.Volumes, CleanItem{ID: volume.Name, Name: volume.Name, Size: volume.Size()})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// Clean supprime les ressources du plan : containers, réseaux, images puis volumes.
+// Le plan doit venir de PlanClean (éventuellement affiché et confirmé avant).
+func (m *Manager) Clean(ctx context.Context, p *project.Project, plan *CleanPlan) error {
+	steps := []struct {
+		items []CleanItem
+		label string
+		args  []string
+	}{
+		{plan.Containers, "containers", []string{"rm", "-f"}},
+		{plan.Networks, "réseaux", []string{"network", "rm"}},
+		// -f : une image peut porter plusieurs tags ; celles utilisées par
+		// d'autres containers ont déjà été exclues par PlanClean
+		{plan.Images, "images", []string{"image", "rm", "-f"}},
+		{plan.Volumes, "volumes", []string{"volume", "rm"}},
+	}
+	if plan.Level >= CleanVolumes {
+		// Supprimer aussi les volumes anonymes des containers
+		steps[0].args = append(steps[0].args, "-v")
+	}
+
+	for _, step := range steps {
+		if len(step.items) == 0 {
+			continue
+		}
+		m.report(p, "clean", false, "🧹 Suppression de %d %s...", len(step.items), step.label)
+
+		args := append([]string(nil), step.args...)
+		for _, item := range step.items {
+			args = append(args, item.ID)
+		}
+		stepCtx, cancel := withTimeout(ctx, m.Timeouts.Down)
+		_, err := dockerOutput(stepCtx, args...)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("erreur lors de la suppression des %s: %w", step.label, err)
+		}
+	}
+
+	m.report(p, "clean", true, "✅ Projet %s nettoyé (%s)", p.Name, plan.Level)
+	return nil
+}
+
+// allServices retourne les services du compose, tous profils confondus
+// (un service d'un profil inactif n'est pas orphelin)
+func (m *Manager) allServices(ctx context.Context, p *project.Project) (map[string]bool, error) {
+	withProfiles := *p
+	if profiles, err := m.GetProfiles(ctx, p); err == nil {
+		withProfiles.Profiles = profiles
+	}
+
+	names, err := m.GetServices(ctx, &withProfiles)
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]bool, len(names))
+	for _, name := range names {
+		services[name] = true
+	}
+	return services, nil
+}
+
+// projectNetworks liste les réseaux créés par compose pour le projet
+func (m *Manager) projectNetworks(ctx context.Context, p *project.Project) ([]CleanItem, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	filter := fmt.Sprintf("%s=%s", LabelProject, p.Name)
+	var items []CleanItem
+	if m.Engine != nil {
+		if networks, err := m.Engine.ListNetworks(ctx, map[string][]string{"label": {filter}}); err == nil {
+			for _, network := range networks {
+				items = append(items, CleanItem{ID: shortID(network.ID), Name: network.Name})
+			}
+			return items, nil
+		}
+	}
+
+	output, err := dockerOutput(ctx, "network", "ls", "--filter", "label="+filter, "--format", "{{.ID}}\t{{.Name}}")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des réseaux: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if id, name, ok := strings.Cut(line, "\t"); ok {
+			items = append(items, CleanItem{ID: id, Name: name})
+		}
+	}
+	return items, nil
+}
+
+// builtByProject indique une image construite par compose pour un service du
+// projet (<projet>-<service> en v2, <projet>_<service> en v1)
+func builtByProject(tags []string, projectName string, services map[string]bool) bool {
+	for _, tag := range tags {
+		repo := tag
+		if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+			repo = repo[:i]
+		}
+		for service := range services {
+			if repo == projectName+"-"+service || repo == projectName+"_"+service {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anyTag indique si l'un des tags de l'image est dans l'ensemble
+func anyTag(tags []string, set map[string]bool) bool {
+	for _, tag := range tags {
+		if set[tag] || set[strings.TrimSuffix(tag, ":latest")] {
+			return true
+		}
+	}
+	return false
+}
+
+// imageName retourne le premier tag de l'image, ou son identifiant
+func imageName(image ImageSummary) string {
+	if len(image.RepoTags) > 0 && image.RepoTags[0] != "<none>:<none>" {
+		return image.RepoTags[0]
+	}
+	return shortImageID(image.ID)
+}
+
+// shortImageID retire le préfixe sha256: et tronque l'identifiant
+func shortImageID(id string) string {
+	return shortID(strings.TrimPrefix(id, "sha256:"))
+}
+
+// containerName retourne le nom principal d'un container (sans le / initial)
+func containerName(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(names[0], "/")
+}
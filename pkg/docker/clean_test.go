@@ -0,0 +1,125 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cleanEngine simule /system/df et /networks pour PlanClean
+func cleanEngine(t *testing.T, usage SystemDiskUsage) *EngineClient {
+	return newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/system/df":
+			json.NewEncoder(w).Encode(usage)
+		case "/networks":
+			json.NewEncoder(w).Encode([]Network{{ID: "net0123456789ab", Name: "web_default"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// cleanServices répond aux requêtes config : services app et db
+func cleanServices(args []string) string {
+	if args[len(args)-1] == "--services" {
+		return "app\ndb\n"
+	}
+	return ""
+}
+
+// itemNames retourne les noms des ressources d'une section du plan
+func itemNames(items []CleanItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestPlanClean(t *testing.T) {
+	usage := SystemDiskUsage{
+		Containers: []ContainerDiskUsage{
+			{ID: "c1", Names: []string{"/web-app-1"}, Image: "web-app", ImageID: "sha256:webapp", Labels: composeLabels("web", "app"), State: "running"},
+			{ID: "c2", Names: []string{"/web-db-1"}, Image: "postgres:16", ImageID: "sha256:postgres", Labels: composeLabels("web", "db"), State: "exited"},
+			{ID: "c3", Names: []string{"/web-old-1"}, Image: "web-old", ImageID: "sha256:webold", Labels: composeLabels("web", "old"), State: "exited"},
+			{ID: "c4", Names: []string{"/blog-db-1"}, Image: "postgres:15", ImageID: "sha256:postgres15", Labels: composeLabels("blog", "db"), State: "running"},
+		},
+		Images: []ImageSummary{
+			{ID: "sha256:webapp", RepoTags: []string{"web-app:latest"}},
+			{ID: "sha256:postgres", RepoTags: []string{"postgres:16"}},
+			{ID: "sha256:postgres15", RepoTags: []string{"postgres:15"}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		level          CleanLevel
+		includeRunning bool
+		wantContainers []string
+		wantKept       []string
+		wantNetworks   []string
+		wantImages     []string
+	}{
+		{"orphelins", CleanOrphans, false, []string{"web-old-1"}, nil, nil, nil},
+		// app démarré : conservé avec son image et le réseau du projet
+		{"images", CleanImages, false, []string{"web-db-1", "web-old-1"}, []string{"web-app-1"}, nil, nil},
+		{"images avec démarrés", CleanImages, true, []string{"web-app-1", "web-db-1", "web-old-1"}, nil, []string{"web_default"}, []string{"web-app:latest"}},
+		{"all", CleanAll, false, []string{"web-db-1", "web-old-1"}, []string{"web-app-1"}, nil, []string{"postgres:16"}},
+	}
+	for _, tt := range tests {
+		mgr := &Manager{Runner: (&fakeRunner{output: cleanServices}).runner(), Engine: cleanEngine(t, usage)}
+		plan, err := mgr.PlanClean(context.Background(), testProject(), tt.level, tt.includeRunning)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := itemNames(plan.Containers); !reflect.DeepEqual(got, tt.wantContainers) {
+			t.Errorf("%s : containers = %q, attendu %q", tt.name, got, tt.wantContainers)
+		}
+		if got := itemNames(plan.Kept); !reflect.DeepEqual(got, tt.wantKept) {
+			t.Errorf("%s : conservés = %q, attendu %q", tt.name, got, tt.wantKept)
+		}
+		if got := itemNames(plan.Networks); !reflect.DeepEqual(got, tt.wantNetworks) {
+			t.Errorf("%s : réseaux = %q, attendu %q", tt.name, got, tt.wantNetworks)
+		}
+		if got := itemNames(plan.Images); !reflect.DeepEqual(got, tt.wantImages) {
+			t.Errorf("%s : images = %q, attendu %q", tt.name, got, tt.wantImages)
+		}
+
+		wantRunning := 0
+		if tt.includeRunning {
+			wantRunning = 1
+		}
+		if got := plan.RunningContainers(); got != wantRunning {
+			t.Errorf("%s : %d container(s) démarré(s) à arrêter, attendu %d", tt.name, got, wantRunning)
+		}
+		for _, item := range append(plan.Containers, plan.Kept...) {
+			if item.Running && !strings.Contains(item.Note, "en cours d'exécution") {
+				t.Errorf("%s : %s démarré sans mention dans le plan (%q)", tt.name, item.Name, item.Note)
+			}
+		}
+	}
+}
+
+func TestParseSystemDFState(t *testing.T) {
+	output := `{"Containers":[` +
+		`{"ID":"c1","Names":"web-app-1","State":"running","Status":"Up 2 hours","Labels":"","Size":"1kB"},` +
+		`{"ID":"c2","Names":"web-db-1","Status":"Up 3 minutes","Labels":"","Size":"0B"},` +
+		`{"ID":"c3","Names":"web-old-1","Status":"Exited (0) 2 days ago","Labels":"","Size":"0B"}]}`
+	usage, err := parseSystemDF([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []bool
+	for _, container := range usage.Containers {
+		got = append(got, container.Active())
+	}
+	if want := []bool{true, true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("démarrés = %v, attendu %v", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/config"
+)
+
+// ComposeVariant identifie l'implémentation de Docker Compose utilisée
+type ComposeVariant string
+
+const (
+	// ComposeAuto détecte la variante disponible au premier appel
+	ComposeAuto ComposeVariant = "auto"
+	// ComposeV1 utilise le binaire historique docker-compose
+	ComposeV1 ComposeVariant = "v1"
+	// ComposeV2 utilise le plugin docker compose
+	ComposeV2 ComposeVariant = "v2"
+)
+
+// ComposeEnvVar permet de forcer la variante de compose (auto, v1, v2)
+const ComposeEnvVar = "DOCKER_MANAGER_COMPOSE"
+
+// ParseComposeVariant convertit une valeur de config en variante
+func ParseComposeVariant(value string) (ComposeVariant, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "auto":
+		return ComposeAuto, nil
+	case "v1", "docker-compose":
+		return ComposeV1, nil
+	case "v2", "plugin", "docker compose":
+		return ComposeV2, nil
+	default:
+		return "", fmt.Errorf("variante compose inconnue: %q (attendu: auto, v1, v2)", value)
+	}
+}
+
+// Invocation décrit un appel à docker compose
+type Invocation struct {
+	Dir    string
+	Args   []string
+	Env    []string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ComposeRunner exécute les commandes docker compose.
+// Le Manager passe toujours par cette interface, ce qui permet de la
+// remplacer par un faux runner dans les tests.
+type ComposeRunner interface {
+	Run(ctx context.Context, inv Invocation) error
+}
+
+// RunnerFunc adapte une fonction en ComposeRunner
+type RunnerFunc func(ctx context.Context, inv Invocation) error
+
+// Run appelle la fonction
+func (f RunnerFunc) Run(ctx context.Context, inv Invocation) error {
+	return f(ctx, inv)
+}
+
+// cancelGracePeriod laisse à compose le temps de s'arrêter après un SIGINT
+const cancelGracePeriod = 10 * time.Second
+
+// CLIRunner exécute compose via le binaire docker-compose ou le plugin docker compose
+type CLIRunner struct {
+	Variant ComposeVariant
+	bin     string
+	prefix  []string
+}
+
+// NewCLIRunner crée un runner pour une variante explicite (v1 ou v2)
+func NewCLIRunner(variant ComposeVariant) *CLIRunner {
+	if variant == ComposeV1 {
+		return &CLIRunner{Variant: ComposeV1, bin: "docker-compose"}
+	}
+	return &CLIRunner{Variant: ComposeV2, bin: "docker", prefix: []string{"compose"}}
+}
+
+// Run exécute la commande compose
+func (r *CLIRunner) Run(ctx context.Context, inv Invocation) error {
+	args := append(append([]string{}, r.prefix...), inv.Args...)
+	cmd := exec.CommandContext(ctx, r.bin, args...)
+	cmd.Dir = inv.Dir
+	cmd.Stdin = inv.Stdin
+	cmd.Stdout = inv.Stdout
+	cmd.Stderr = inv.Stderr
+	if len(inv.Env) > 0 {
+		cmd.Env = append(os.Environ(), inv.Env...)
+	}
+
+	// À l'annulation, laisser compose s'arrêter proprement (SIGINT)
+	// avant de forcer la fin du processus
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = cancelGracePeriod
+	return cmd.Run()
+}
+
+// String retourne la commande utilisée (ex: "docker compose")
+func (r *CLIRunner) String() string {
+	return strings.Join(append([]string{r.bin}, r.prefix...), " ")
+}
+
+// autoRunner choisit la variante au premier appel puis la garde en cache
+type autoRunner struct {
+	once   sync.Once
+	runner *CLIRunner
+	err    error
+}
+
+func (a *autoRunner) resolve() (*CLIRunner, error) {
+	a.once.Do(func() {
+		a.runner, a.err = DetectCompose()
+	})
+	return a.runner, a.err
+}
+
+// Run détecte la variante si nécessaire puis exécute la commande
+func (a *autoRunner) Run(ctx context.Context, inv Invocation) error {
+	runner, err := a.resolve()
+	if err != nil {
+		return err
+	}
+	return runner.Run(ctx, inv)
+}
+
+var defaultRunner = &autoRunner{}
+
+// invalidRunner signale une variante compose mal configurée à chaque commande,
+// plutôt que de retomber silencieusement sur la détection automatique
+type invalidRunner struct {
+	err error
+}
+
+// Run retourne l'erreur de configuration
+func (r invalidRunner) Run(ctx context.Context, inv Invocation) error {
+	return r.err
+}
+
+func (r invalidRunner) String() string {
+	return "invalide (" + r.err.Error() + ")"
+}
+
+// DefaultComposeRunner retourne le runner selon DOCKER_MANAGER_COMPOSE,
+// puis le champ compose de la config, sinon la détection automatique.
+// Une valeur inconnue fait échouer chaque commande compose avec l'erreur.
+func DefaultComposeRunner() ComposeRunner {
+	source := ComposeEnvVar
+	value := os.Getenv(ComposeEnvVar)
+	if value == "" {
+		source = "compose (projects.yml)"
+		if cfg, err := config.LoadConfig(); err == nil {
+			value = cfg.Compose
+		}
+	}
+
+	variant, err := ParseComposeVariant(value)
+	if err != nil {
+		return invalidRunner{err: fmt.Errorf("%s: %w", source, err)}
+	}
+	if variant == ComposeAuto {
+		return defaultRunner
+	}
+	return NewCLIRunner(variant)
+}
+
+// DetectCompose sonde le plugin v2 puis le binaire v1
+func DetectCompose() (*CLIRunner, error) {
+	for _, variant := range []ComposeVariant{ComposeV2, ComposeV1} {
+		runner := NewCLIRunner(variant)
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeouts().Daemon)
+		err := runner.Run(ctx, Invocation{Args: []string{"version"}})
+		cancel()
+		if err == nil {
+			return runner, nil
+		}
+	}
+	return nil, fmt.Errorf("ni 'docker compose' ni 'docker-compose' n'est disponible")
+}
+
+// ComposeDescription retourne la commande compose effectivement utilisée
+func ComposeDescription(r ComposeRunner) string {
+	switch runner := r.(type) {
+	case *autoRunner:
+		resolved, err := runner.resolve()
+		if err != nil {
+			return "introuvable"
+		}
+		return resolved.String()
+	case fmt.Stringer:
+		return runner.String()
+	default:
+		return "personnalisé"
+	}
+}
+
+// CommandError conserve la sortie d'erreur d'une commande échouée
+type CommandError struct {
+	Err    error
+	Stderr string
+}
+
+// Error retourne stderr si disponible, sinon l'erreur d'exécution
+func (e *CommandError) Error() string {
+	if e.Stderr != "" {
+		return e.Stderr
+	}
+	return e.Err.Error()
+}
+
+// Unwrap retourne l'erreur d'exécution sous-jacente
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,218 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// fakeRunner enregistre les commandes compose au lieu de les exécuter
+type fakeRunner struct {
+	mu    sync.Mutex
+	calls []Invocation
+	// output retourne la sortie standard d'une commande (nil : aucune sortie)
+	output func(args []string) string
+}
+
+func (r *fakeRunner) runner() RunnerFunc {
+	return func(ctx context.Context, inv Invocation) error {
+		r.mu.Lock()
+		r.calls = append(r.calls, inv)
+		r.mu.Unlock()
+		if r.output != nil && inv.Stdout != nil {
+			fmt.Fprint(inv.Stdout, r.output(inv.Args))
+		}
+		return nil
+	}
+}
+
+// commands retourne les sous-commandes exécutées (sans le préfixe -f/-p/...),
+// hors requêtes de lecture (config)
+func (r *fakeRunner) commands(t *testing.T, base []string) [][]string {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var commands [][]string
+	for _, inv := range r.calls {
+		if len(inv.Args) < len(base) || !reflect.DeepEqual(inv.Args[:len(base)], base) {
+			t.Fatalf("préfixe compose = %q, attendu %q", inv.Args, base)
+		}
+		args := inv.Args[len(base):]
+		if len(args) > 0 && args[0] == "config" {
+			continue
+		}
+		commands = append(commands, args)
+	}
+	return commands
+}
+
+func TestParseComposeVariant(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ComposeVariant
+	}{
+		{"", ComposeAuto},
+		{"auto", ComposeAuto},
+		{"v1", ComposeV1},
+		{"docker-compose", ComposeV1},
+		{" V2 ", ComposeV2},
+		{"plugin", ComposeV2},
+		{"docker compose", ComposeV2},
+	}
+	for _, tt := range tests {
+		got, err := ParseComposeVariant(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseComposeVariant(%q) = %q, %v, attendu %q", tt.value, got, err, tt.want)
+		}
+	}
+
+	if _, err := ParseComposeVariant("v3"); err == nil {
+		t.Error("ParseComposeVariant(v3): erreur attendue")
+	}
+}
+
+func TestDefaultComposeRunner(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"v1", "docker-compose"},
+		{"v2", "docker compose"},
+		{"plugin", "docker compose"},
+	}
+	for _, tt := range tests {
+		t.Setenv(ComposeEnvVar, tt.env)
+		if got := ComposeDescription(DefaultComposeRunner()); got != tt.want {
+			t.Errorf("%s=%s : runner %q, attendu %q", ComposeEnvVar, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultComposeRunnerInvalidVariant(t *testing.T) {
+	t.Setenv(ComposeEnvVar, "v3")
+
+	runner := DefaultComposeRunner()
+	err := runner.Run(context.Background(), Invocation{})
+	if err == nil || !strings.Contains(err.Error(), ComposeEnvVar) {
+		t.Fatalf("erreur = %v, attendu une erreur citant %s", err, ComposeEnvVar)
+	}
+	if got := ComposeDescription(runner); !strings.HasPrefix(got, "invalide (") {
+		t.Errorf("description = %q", got)
+	}
+}
+
+func TestComposeDescriptionCustomRunner(t *testing.T) {
+	runner := RunnerFunc(func(ctx context.Context, inv Invocation) error { return nil })
+	if got := ComposeDescription(runner); got != "personnalisé" {
+		t.Errorf("description = %q, attendu personnalisé", got)
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	cause := errors.New("exit status 1")
+
+	withStderr := &CommandError{Err: cause, Stderr: "no such service: db"}
+	if withStderr.Error() != "no such service: db" || !errors.Is(withStderr, cause) {
+		t.Errorf("erreur = %q", withStderr.Error())
+	}
+	if bare := (&CommandError{Err: cause}); bare.Error() != "exit status 1" {
+		t.Errorf("erreur sans stderr = %q", bare.Error())
+	}
+}
+
+func TestManagerRunsComposeThroughRunner(t *testing.T) {
+	fake := &fakeRunner{output: func(args []string) string { return "app\ndb\n" }}
+	mgr := &Manager{Runner: fake.runner()}
+	p := &project.Project{Name: "web", Path: "/srv/docker-web"}
+
+	services, err := mgr.GetServices(context.Background(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(services, []string{"app", "db"}) {
+		t.Errorf("services = %q", services)
+	}
+
+	if len(fake.calls) != 1 {
+		t.Fatalf("%d appels au runner, attendu 1", len(fake.calls))
+	}
+	inv := fake.calls[0]
+	want := []string{"-f", "docker-compose.yml", "-p", "web", "config", "--services"}
+	if inv.Dir != "/srv/docker-web" || !reflect.DeepEqual(inv.Args, want) {
+		t.Errorf("invocation = %q dans %q, attendu %q", inv.Args, inv.Dir, want)
+	}
+}
+
+func TestManagerKeepsRunnerStderr(t *testing.T) {
+	runner := RunnerFunc(func(ctx context.Context, inv Invocation) error {
+		fmt.Fprintln(inv.Stderr, "service \"db\" has neither an image nor a build context")
+		return errors.New("exit status 15")
+	})
+	mgr := &Manager{Runner: runner}
+
+	_, err := mgr.GetServices(context.Background(), &project.Project{Name: "web"})
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || !strings.Contains(cmdErr.Stderr, "neither an image") {
+		t.Errorf("erreur = %v, attendu une CommandError avec stderr", err)
+	}
+}
+
+func TestComposeArgsKeepsFileOrder(t *testing.T) {
+	mgr := &Manager{}
+	p := &project.Project{Name: "web", ComposeFiles: []string{"docker-compose.yml", "docker-compose.prod.yml", "docker-compose.local.yml"}}
+
+	got := mgr.composeArgs(p, "up", "-d")
+	want := []string{
+		"-f", "docker-compose.yml", "-f", "docker-compose.prod.yml", "-f", "docker-compose.local.yml",
+		"-p", "web", "up", "-d",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("composeArgs = %q, attendu %q", got, want)
+	}
+}
+
+func TestManagerInjectsProjectEnv(t *testing.T) {
+	fake := &fakeRunner{}
+	mgr := &Manager{Runner: fake.runner()}
+
+	if err := mgr.compose(context.Background(), testProject(), "up", "-d"); err != nil {
+		t.Fatal(err)
+	}
+	inv := fake.calls[0]
+	if !reflect.DeepEqual(inv.Env, []string{"APP_ENV=test"}) {
+		t.Errorf("env = %q, attendu APP_ENV=test", inv.Env)
+	}
+	want := []string{
+		"-f", "docker-compose.yml", "-f", "docker-compose.override.yml", "-p", "web",
+		"--profile", "debug", "--env-file", ".env.local", "up", "-d",
+	}
+	if !reflect.DeepEqual(inv.Args, want) {
+		t.Errorf("args = %q, attendu %q", inv.Args, want)
+	}
+}
+
+func TestCLIRunnerPassesEnv(t *testing.T) {
+	t.Setenv("DOCKER_MANAGER_TEST_INHERITED", "hérité")
+	// sh remplace le binaire compose : les arguments deviennent le script
+	runner := &CLIRunner{bin: "sh", prefix: []string{"-c"}}
+
+	var stdout strings.Builder
+	err := runner.Run(context.Background(), Invocation{
+		Args:   []string{`printf '%s %s' "$APP_ENV" "$DOCKER_MANAGER_TEST_INHERITED"`},
+		Env:    []string{"APP_ENV=test"},
+		Stdout: &stdout,
+	})
+	if err != nil {
+		t.Skipf("sh indisponible: %v", err)
+	}
+	if got := stdout.String(); got != "test hérité" {
+		t.Errorf("environnement = %q, attendu la variable du projet et celles du processus", got)
+	}
+}
@@ -0,0 +1,414 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiskUsage est l'espace disque occupé, par type de ressource
+type DiskUsage struct {
+	Images     int64 // taille unique des images (couches non partagées)
+	Containers int64 // couches inscriptibles des containers
+	Volumes    int64
+	BuildCache int64
+}
+
+// Total retourne l'espace occupé, tous types confondus
+func (u DiskUsage) Total() int64 {
+	return u.Images + u.Containers + u.Volumes + u.BuildCache
+}
+
+// Add cumule une autre occupation disque
+func (u *DiskUsage) Add(other DiskUsage) {
+	u.Images += other.Images
+	u.Containers += other.Containers
+	u.Volumes += other.Volumes
+	u.BuildCache += other.BuildCache
+}
+
+// ProjectDiskUsage est l'espace disque attribué à un projet
+type ProjectDiskUsage struct {
+	Name string
+	DiskUsage
+}
+
+// DiskReport répartit l'espace disque Docker entre les projets
+type DiskReport struct {
+	Projects []ProjectDiskUsage // triés par taille décroissante
+	// Shared regroupe les images utilisées par plusieurs projets
+	Shared DiskUsage
+	// Unattributed regroupe ce qui n'appartient à aucun projet
+	// (images et volumes hors compose, cache de build)
+	Unattributed DiskUsage
+}
+
+// systemDiskUsage interroge /system/df, ou docker system df -v en repli.
+// Le calcul des tailles peut être long sur une machine chargée.
+func (m *Manager) systemDiskUsage(ctx context.Context) (*SystemDiskUsage, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Down)
+	defer cancel()
+
+	if m.Engine != nil {
+		if usage, err := m.Engine.DiskUsage(ctx); err == nil {
+			return usage, nil
+		}
+	}
+
+	output, err := dockerOutput(ctx, "system", "df", "-v", "--format", "{{json .}}")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du calcul de l'espace disque: %w", err)
+	}
+	return parseSystemDF(output)
+}
+
+// parseSystemDF convertit la sortie de docker system df -v --format '{{json .}}'
+// (tailles lisibles, labels "clé=valeur,...") au format de l'API
+func parseSystemDF(output []byte) (*SystemDiskUsage, error) {
+	var raw struct {
+		Images []struct {
+			ID         string `json:"ID"`
+			Repository string `json:"Repository"`
+			Tag        string `json:"Tag"`
+			Size       string `json:"Size"`
+			UniqueSize string `json:"UniqueSize"`
+		} `json:"Images"`
+		Containers []struct {
+			ID     string `json:"ID"`
+			Image  string `json:"Image"`
+			Names  string `json:"Names"`
+			Labels string `json:"Labels"`
+			State  string `json:"State"`
+			Status string `json:"Status"`
+			Size   string `json:"Size"`
+		} `json:"Containers"`
+		Volumes []struct {
+			Name   string `json:"Name"`
+			Labels string `json:"Labels"`
+			Size   string `json:"Size"`
+		} `json:"Volumes"`
+		BuildCache []struct {
+			ID          string `json:"ID"`
+			Description string `json:"Description"`
+			Size        string `json:"Size"`
+		} `json:"BuildCache"`
+	}
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("sortie de docker system df invalide: %w", err)
+	}
+
+	usage := &SystemDiskUsage{}
+	for _, image := range raw.Images {
+		summary := ImageSummary{ID: image.ID, Size: int64(parseSize(image.Size))}
+		if image.UniqueSize != "" {
+			summary.SharedSize = summary.Size - int64(parseSize(image.UniqueSize))
+		}
+		if image.Repository != "" && image.Repository != "<none>" {
+			summary.RepoTags = []string{image.Repository + ":" + image.Tag}
+		}
+		usage.Images = append(usage.Images, summary)
+	}
+	for _, container := range raw.Containers {
+		// Size vaut "2B (virtual 187MB)"
+		size, _, _ := strings.Cut(container.Size, " (")
+		// Les anciennes versions du CLI n'ont que Status ("Up 2 hours")
+		state := container.State
+		if state == "" && strings.HasPrefix(container.Status, "Up") {
+			state = "running"
+		}
+		usage.Containers = append(usage.Containers, ContainerDiskUsage{
+			ID:     container.ID,
+			Names:  []string{container.Names},
+			Image:  container.Image,
+			Labels: parseLabels(container.Labels),
+			State:  state,
+			SizeRw: int64(parseSize(size)),
+		})
+	}
+	for _, volume := range raw.Volumes {
+		v := Volume{Name: volume.Name, Labels: parseLabels(volume.Labels)}
+		v.UsageData = &struct {
+			Size     int64 `json:"Size"`
+			RefCount int64 `json:"RefCount"`
+		}{Size: int64(parseSize(volume.Size))}
+		usage.Volumes = append(usage.Volumes, v)
+	}
+	for _, record := range raw.BuildCache {
+		usage.BuildCache = append(usage.BuildCache, BuildCacheRecord{
+			ID:          record.ID,
+			Description: record.Description,
+			Size:        int64(parseSize(record.Size)),
+		})
+	}
+	return usage, nil
+}
+
+// parseLabels lit les labels au format "clé=valeur,clé=valeur" du CLI
+func parseLabels(value string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if key, val, ok := strings.Cut(pair, "="); ok {
+			labels[strings.TrimSpace(key)] = val
+		}
+	}
+	return labels
+}
+
+// GetDiskUsage attribue l'espace disque Docker aux projets : containers et
+// volumes par le label compose, images par le label compose, par le nom
+// des images construites (<projet>-<service> ou <projet>_<service>, pour un
+// service connu du projet) et par les containers qui les utilisent. Le cache
+// de build est attribué d'après la description des étapes (voir cacheOwner)
+// et la chaîne des parents.
+func (m *Manager) GetDiskUsage(ctx context.Context) (*DiskReport, error) {
+	usage, err := m.systemDiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return attributeDiskUsage(usage), nil
+}
+
+// projectServices associe chaque projet aux services vus sur le daemon
+// (labels compose de ses containers et de ses images)
+type projectServices map[string]map[string]bool
+
+// add enregistre le projet et, s'il est renseigné, son service
+func (k projectServices) add(project, service string) {
+	if k[project] == nil {
+		k[project] = make(map[string]bool)
+	}
+	if service != "" {
+		k[project][service] = true
+	}
+}
+
+// uniqueServices associe chaque service connu d'un seul projet à ce projet
+func (k projectServices) uniqueServices() map[string]string {
+	owners := make(map[string]string)
+	shared := make(map[string]bool)
+	for name, services := range k {
+		for service := range services {
+			if _, seen := owners[service]; seen {
+				shared[service] = true
+			}
+			owners[service] = name
+		}
+	}
+	for service := range shared {
+		delete(owners, service)
+	}
+	return owners
+}
+
+// attributeDiskUsage répartit le résultat de /system/df entre les projets
+func attributeDiskUsage(usage *SystemDiskUsage) *DiskReport {
+	byProject := make(map[string]*DiskUsage)
+	project := func(name string) *DiskUsage {
+		if byProject[name] == nil {
+			byProject[name] = &DiskUsage{}
+		}
+		return byProject[name]
+	}
+
+	// Services de chaque projet d'après les labels : seuls leurs noms
+	// d'images construites (<projet>-<service>) sont reconnus
+	known := make(projectServices)
+	for _, container := range usage.Containers {
+		if name := container.Labels[LabelProject]; name != "" {
+			known.add(name, container.Labels[LabelService])
+		}
+	}
+	for _, image := range usage.Images {
+		if name := image.Labels[LabelProject]; name != "" {
+			known.add(name, image.Labels[LabelService])
+		}
+	}
+
+	report := &DiskReport{}
+
+	// Containers : label compose ; mémoriser quels projets utilisent chaque image
+	imageUsers := make(map[string]map[string]bool)
+	for _, container := range usage.Containers {
+		name := container.Labels[LabelProject]
+		if name == "" {
+			report.Unattributed.Containers += container.SizeRw
+			continue
+		}
+		project(name).Containers += container.SizeRw
+
+		for _, ref := range []string{container.ImageID, container.Image} {
+			if ref == "" {
+				continue
+			}
+			if imageUsers[ref] == nil {
+				imageUsers[ref] = make(map[string]bool)
+			}
+			imageUsers[ref][name] = true
+		}
+	}
+
+	// Volumes : label compose
+	for _, volume := range usage.Volumes {
+		if name := volume.Labels[LabelProject]; name != "" {
+			project(name).Volumes += volume.Size()
+		} else {
+			report.Unattributed.Volumes += volume.Size()
+		}
+	}
+
+	// Images : label, nom de l'image, containers qui l'utilisent
+	for _, image := range usage.Images {
+		owners := make(map[string]bool)
+		if name := image.Labels[LabelProject]; name != "" {
+			owners[name] = true
+		}
+		for _, tag := range image.RepoTags {
+			if name := projectFromImageName(tag, known); name != "" {
+				owners[name] = true
+			}
+			for name := range imageUsers[tag] {
+				owners[name] = true
+			}
+			if repo, found := strings.CutSuffix(tag, ":latest"); found {
+				for name := range imageUsers[repo] {
+					owners[name] = true
+				}
+			}
+		}
+		for name := range imageUsers[image.ID] {
+			owners[name] = true
+		}
+
+		switch len(owners) {
+		case 0:
+			report.Unattributed.Images += image.UniqueSize()
+		case 1:
+			for name := range owners {
+				project(name).Images += image.UniqueSize()
+			}
+		default:
+			report.Shared.Images += image.UniqueSize()
+		}
+	}
+
+	// Cache de build : un service connu d'un seul projet identifie l'étape
+	for name, size := range attributeBuildCache(usage.BuildCache, known) {
+		if name == "" {
+			report.Unattributed.BuildCache += size
+		} else {
+			project(name).BuildCache += size
+		}
+	}
+
+	for name, projectUsage := range byProject {
+		report.Projects = append(report.Projects, ProjectDiskUsage{Name: name, DiskUsage: *projectUsage})
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		if report.Projects[i].Total() != report.Projects[j].Total() {
+			return report.Projects[i].Total() > report.Projects[j].Total()
+		}
+		return report.Projects[i].Name < report.Projects[j].Name
+	})
+	return report
+}
+
+// attributeBuildCache répartit le cache de build entre les projets (clé vide :
+// non attribué). Un enregistrement sans indice hérite du projet de ses parents :
+// les étapes RUN/COPY descendent de l'étape FROM de la même cible.
+func attributeBuildCache(records []BuildCacheRecord, known projectServices) map[string]int64 {
+	services := known.uniqueServices()
+	byID := make(map[string]BuildCacheRecord, len(records))
+	for _, record := range records {
+		byID[record.ID] = record
+	}
+
+	owners := make(map[string]string)
+	var ownerOf func(id string) string
+	ownerOf = func(id string) string {
+		if owner, done := owners[id]; done {
+			return owner
+		}
+		owners[id] = "" // protège contre un cycle de parents
+		record, ok := byID[id]
+		if !ok {
+			return ""
+		}
+
+		owner := cacheOwner(record.Description, known, services)
+		for _, parent := range record.parents() {
+			if owner != "" {
+				break
+			}
+			owner = ownerOf(parent)
+		}
+		owners[id] = owner
+		return owner
+	}
+
+	sizes := make(map[string]int64)
+	for _, record := range records {
+		sizes[ownerOf(record.ID)] += record.Size
+	}
+	return sizes
+}
+
+// cacheOwner retrouve le projet d'une étape de build d'après sa description :
+// cible nommée "[service 2/5] RUN ..." (build compose/bake, services : service
+// connu d'un seul projet), ou image d'un projet citée dans l'étape
+// ("FROM shop-base:latest"). Les étapes internes ("[internal] load build
+// context") n'ont pas de propriétaire.
+func cacheOwner(description string, known projectServices, services map[string]string) string {
+	if strings.HasPrefix(description, "[") {
+		if end := strings.Index(description, "]"); end > 0 {
+			// "[2/5]" n'a pas de nom de cible
+			if fields := strings.Fields(description[1:end]); len(fields) > 1 {
+				if owner := services[fields[0]]; owner != "" {
+					return owner
+				}
+			}
+		}
+	}
+
+	for _, token := range strings.Fields(description) {
+		token, _, _ = strings.Cut(token, "@")
+		if !strings.Contains(token, ":") {
+			continue
+		}
+		if name := projectFromImageName(token, known); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// imageRepo retourne le dépôt d'un tag sans registre ni tag ("web-app:1" → "web-app")
+func imageRepo(tag string) string {
+	repo := tag
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo[strings.LastIndex(repo, "/")+1:]
+}
+
+// projectFromImageName retrouve le projet d'une image construite par compose
+// ("web-app:latest" pour le service app du projet web). Seuls les services
+// connus comptent : "redis-stack" n'appartient pas au projet redis s'il n'a
+// pas de service stack. Le nom de projet le plus long gagne.
+func projectFromImageName(tag string, known projectServices) string {
+	repo := imageRepo(tag)
+
+	best := ""
+	for name, services := range known {
+		if len(name) <= len(best) {
+			continue
+		}
+		for _, sep := range []string{"-", "_"} {
+			if service, found := strings.CutPrefix(repo, name+sep); found && services[service] {
+				best = name
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+// composeLabels retourne les labels compose d'un service
+func composeLabels(project, service string) map[string]string {
+	return map[string]string{LabelProject: project, LabelService: service}
+}
+
+func TestAttributeDiskUsage(t *testing.T) {
+	usage := &SystemDiskUsage{
+		Containers: []ContainerDiskUsage{
+			{ID: "c1", Image: "web-app", ImageID: "sha256:webapp", Labels: composeLabels("web", "app"), SizeRw: 10},
+			{ID: "c2", Image: "redis:7", ImageID: "sha256:redis", Labels: composeLabels("redis", "redis"), SizeRw: 20},
+			{ID: "c3", Image: "postgres:16", ImageID: "sha256:postgres", Labels: composeLabels("blog", "db"), SizeRw: 1},
+			{ID: "c4", Image: "postgres:16", ImageID: "sha256:postgres", Labels: composeLabels("shop", "db"), SizeRw: 2},
+			{ID: "c5", Image: "alpine", Labels: map[string]string{}, SizeRw: 5},
+		},
+		Images: []ImageSummary{
+			{ID: "sha256:webapp", RepoTags: []string{"web-app:latest"}, Size: 100},
+			// Construite pour web mais sans container : nom <projet>-<service>
+			{ID: "sha256:webworker", RepoTags: []string{"web_worker:latest"}, Labels: composeLabels("web", "worker"), Size: 50},
+			{ID: "sha256:webold", RepoTags: []string{"registry.local/web-app:v1"}, Size: 40, SharedSize: 30},
+			{ID: "sha256:redis", RepoTags: []string{"redis:7"}, Size: 200},
+			// redis n'a pas de service stack : image hors projet
+			{ID: "sha256:stack", RepoTags: []string{"redis-stack:latest"}, Size: 300},
+			{ID: "sha256:postgres", RepoTags: []string{"postgres:16"}, Size: 400},
+		},
+		Volumes: []Volume{
+			{Name: "web_data", Labels: map[string]string{LabelProject: "web", LabelVolume: "data"}},
+			{Name: "orphan"},
+		},
+	}
+	usage.Volumes[0].UsageData = &struct {
+		Size     int64 `json:"Size"`
+		RefCount int64 `json:"RefCount"`
+	}{Size: 1000}
+
+	report := attributeDiskUsage(usage)
+
+	got := make(map[string]DiskUsage)
+	var order []string
+	for _, p := range report.Projects {
+		got[p.Name] = p.DiskUsage
+		order = append(order, p.Name)
+	}
+	want := map[string]DiskUsage{
+		"web":   {Images: 160, Containers: 10, Volumes: 1000},
+		"redis": {Images: 200, Containers: 20},
+		"blog":  {Containers: 1},
+		"shop":  {Containers: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("projets = %+v, attendu %+v", got, want)
+	}
+	if wantOrder := []string{"web", "redis", "shop", "blog"}; !reflect.DeepEqual(order, wantOrder) {
+		t.Errorf("ordre = %q, attendu %q", order, wantOrder)
+	}
+	if report.Shared != (DiskUsage{Images: 400}) {
+		t.Errorf("partagé = %+v, attendu l'image postgres", report.Shared)
+	}
+	if report.Unattributed != (DiskUsage{Images: 300, Containers: 5}) {
+		t.Errorf("hors projet = %+v, attendu redis-stack et le container alpine", report.Unattributed)
+	}
+}
+
+func TestProjectFromImageName(t *testing.T) {
+	known := projectServices{
+		"web":     {"app": true, "worker": true},
+		"web-api": {"app": true},
+		"redis":   {"redis": true},
+	}
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"web-app:latest", "web"},
+		{"web_worker", "web"},
+		{"ghcr.io/acme/web-app:1.2", "web"},
+		{"web-api-app:latest", "web-api"},
+		{"web-db:latest", ""},
+		{"redis-stack:latest", ""},
+		{"redis:7", ""},
+	}
+	for _, tt := range tests {
+		if got := projectFromImageName(tt.tag, known); got != tt.want {
+			t.Errorf("projectFromImageName(%q) = %q, attendu %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestCacheOwner(t *testing.T) {
+	known := projectServices{
+		"shop": {"base": true, "front": true},
+		"blog": {"front": true, "api": true},
+	}
+	services := known.uniqueServices()
+
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"[api 2/5] RUN npm ci", "blog"},
+		{"[base 1/3] FROM node:20", "shop"},
+		// front existe dans deux projets : pas de propriétaire par la cible
+		{"[front 3/4] COPY . .", ""},
+		{"[front 1/4] FROM shop-base:latest@sha256:abc", "shop"},
+		{"[2/5] RUN go build", ""},
+		{"[internal] load build context", ""},
+		{"mount / from exec /bin/sh -c apk add git", ""},
+	}
+	for _, tt := range tests {
+		if got := cacheOwner(tt.description, known, services); got != tt.want {
+			t.Errorf("cacheOwner(%q) = %q, attendu %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestAttributeBuildCache(t *testing.T) {
+	known := projectServices{"web": {"app": true}}
+	records := []BuildCacheRecord{
+		{ID: "from", Description: "[app 1/3] FROM golang:1.21", Size: 1},
+		{ID: "run", Parent: "from", Description: "mount / from exec /bin/sh -c go build", Size: 2},
+		{ID: "copy", Parents: []string{"run"}, Description: "COPY . .", Size: 4},
+		{ID: "ctx", Description: "[internal] load build context", Size: 8},
+		// Parents en cycle : pas de boucle infinie
+		{ID: "a", Parent: "b", Size: 16},
+		{ID: "b", Parent: "a", Size: 32},
+	}
+
+	got := attributeBuildCache(records, known)
+	want := map[string]int64{"web": 7, "": 56}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cache = %v, attendu %v", got, want)
+	}
+}
@@ -1,131 +1,499 @@
 package docker
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/phil/docker-manager/pkg/config"
 	"github.com/phil/docker-manager/pkg/project"
 )
 
 // Manager gère les opérations Docker
 type Manager struct {
-	WorkDir string
+	WorkDir  string
+	Runner   ComposeRunner
+	Timeouts Timeouts
+	// Flux utilisés par les commandes compose (build, up, logs...).
+	// Le CLI garde os.Stdout/os.Stderr, le dashboard les redirige.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Progress reçoit les étapes des opérations ; si nil, les messages
+	// sont écrits sur Stdout
+	Progress ProgressFunc
+	// Engine est utilisé pour les requêtes de statut s'il est disponible,
+	// sinon le Manager retombe sur le CLI docker
+	Engine *EngineClient
+	// StatusWorkers limite les projets interrogés simultanément
+	// (DefaultStatusWorkers si 0)
+	StatusWorkers int
 }
 
 // NewManager crée un nouveau gestionnaire Docker
 func NewManager(workDir string) *Manager {
+	engine, _ := NewEngineClient()
+	// Une config invalide est signalée une fois au lancement (voir ConfiguredTimeouts)
+	timeouts, _ := ConfiguredTimeouts()
 	return &Manager{
-		WorkDir: workDir,
+		WorkDir:  workDir,
+		Runner:   DefaultComposeRunner(),
+		Timeouts: timeouts,
+		Stdin:    os.Stdin,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		Engine:   engine,
 	}
 }
 
-// StartProject démarre un projet avec build
-func (m *Manager) StartProject(p *project.Project) error {
-	fmt.Printf("🔨 Construction de l'image %s...\n", p.Name)
-	cmd := exec.Command("docker-compose", "-f", "docker-compose.yml", "-p", p.Name, "build")
-	cmd.Dir = p.Path
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// engineContainers liste les containers des services d'un projet via l'API
+// Engine (les containers de compose run sont exclus)
+func (m *Manager) engineContainers(ctx context.Context, p *project.Project, all bool) ([]Container, error) {
+	if m.Engine == nil {
+		return nil, fmt.Errorf("API Docker Engine indisponible")
+	}
+
+	active := m.activeServices(ctx, p)
+
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+	containers, err := m.Engine.ProjectContainers(ctx, p.Name, all)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeContainers(serviceContainers(containers), active), nil
+}
+
+// activeContainers garde les containers des services actifs (active nil : tous)
+func activeContainers(containers []Container, active map[string]bool) []Container {
+	if active == nil {
+		return containers
+	}
+	var selected []Container
+	for _, container := range containers {
+		if active[container.Service()] {
+			selected = append(selected, container)
+		}
+	}
+	return selected
+}
+
+// activeServices retourne les services actifs avec les profils sélectionnés
+// (compose config --services), ou nil sans profil : tous les services comptent
+func (m *Manager) activeServices(ctx context.Context, p *project.Project) map[string]bool {
+	if len(p.Profiles) == 0 {
+		return nil
+	}
+	services, err := m.GetServices(ctx, p)
+	if err != nil {
+		return nil
+	}
+
+	active := make(map[string]bool, len(services))
+	for _, service := range services {
+		active[service] = true
+	}
+	return active
+}
+
+// serviceContainers retire les containers one-off (compose run)
+func serviceContainers(containers []Container) []Container {
+	var services []Container
+	for _, container := range containers {
+		if !container.OneOff() {
+			services = append(services, container)
+		}
+	}
+	return services
+}
+
+// composeArgs préfixe les arguments avec les fichiers compose et le nom du projet
+func (m *Manager) composeArgs(p *project.Project, args ...string) []string {
+	var base []string
+	for _, file := range p.Files() {
+		base = append(base, "-f", file)
+	}
+	base = append(base, "-p", p.Name)
+	for _, profile := range p.Profiles {
+		base = append(base, "--profile", profile)
+	}
+	for _, envFile := range p.EnvFiles {
+		base = append(base, "--env-file", envFile)
+	}
+	return append(base, args...)
+}
+
+// invocation prépare un appel compose pour le projet (dossier, arguments, environnement)
+func (m *Manager) invocation(p *project.Project, args ...string) Invocation {
+	return Invocation{
+		Dir:  p.Path,
+		Args: m.composeArgs(p, args...),
+		Env:  p.EnvList(),
+	}
+}
+
+// compose exécute une commande compose en affichant sa sortie.
+// Le timeout dépend de la sous-commande (args[0]).
+func (m *Manager) compose(ctx context.Context, p *project.Project, args ...string) error {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.forStep(args[0]))
+	defer cancel()
+
+	inv := m.invocation(p, args...)
+	inv.Stdout = m.Stdout
+	inv.Stderr = m.Stderr
+	return stepErr(ctx, args[0], m.Runner.Run(ctx, inv))
+}
+
+// composeOutput exécute une commande compose et retourne sa sortie standard
+func (m *Manager) composeOutput(ctx context.Context, p *project.Project, args ...string) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.forStep(args[0]))
+	defer cancel()
+
+	var stdout bytes.Buffer
+	var stderr strings.Builder
+
+	inv := m.invocation(p, args...)
+	inv.Stdout = &stdout
+	inv.Stderr = &stderr
+	if err := m.Runner.Run(ctx, inv); err != nil {
+		if stepped := stepErr(ctx, args[0], err); stepped != err {
+			return nil, stepped
+		}
+		return nil, &CommandError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.Bytes(), nil
+}
+
+// StartOptions contrôle les étapes de StartProject
+type StartOptions struct {
+	NoBuild       bool     // ne pas construire les images (up --no-build)
+	Pull          bool     // mettre à jour les images avant de démarrer
+	NoCache       bool     // construire sans cache (build --no-cache)
+	ForceRecreate bool     // recréer les containers même sans changement
+	Services      []string // services à démarrer (tous si vide)
+	// IgnorePortConflicts démarre malgré des ports déjà utilisés (simple avertissement)
+	IgnorePortConflicts bool
+	// Wait attend que les services soient opérationnels (voir WaitHealthy)
+	Wait         bool
+	WaitTimeout  time.Duration     // DefaultWaitTimeout si nul
+	HealthChecks map[string]string // service -> health_check (HealthChecksFromConfig)
+}
+
+// StartOptionsFromConfig convertit le mode de démarrage défini dans la config
+func StartOptionsFromConfig(cfg config.StartConfig) StartOptions {
+	return StartOptions{
+		NoBuild:       cfg.NoBuild,
+		Pull:          cfg.Pull,
+		NoCache:       cfg.NoCache,
+		ForceRecreate: cfg.ForceRecreate,
+		Services:      append([]string(nil), cfg.Services...),
+
+		IgnorePortConflicts: cfg.IgnorePortConflicts,
+		Wait:                cfg.Wait,
+		WaitTimeout:         parseWaitTimeout(cfg.WaitTimeout),
+	}
+}
+
+// parseWaitTimeout lit wait_timeout (DefaultWaitTimeout si absent ou invalide)
+func parseWaitTimeout(value string) time.Duration {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return DefaultWaitTimeout
+	}
+	return timeout
+}
+
+// waitOptions retourne les options d'attente de la santé des services
+func (o StartOptions) waitOptions() WaitOptions {
+	return WaitOptions{Services: o.Services, Timeout: o.WaitTimeout, Checks: o.HealthChecks}
+}
+
+// Describe résume le mode de démarrage (ex: "pull, sans build")
+func (o StartOptions) Describe() string {
+	var parts []string
+	if o.Pull {
+		parts = append(parts, "pull")
+	}
+	switch {
+	case o.NoBuild:
+		parts = append(parts, "sans build")
+	case o.NoCache:
+		parts = append(parts, "build sans cache")
+	}
+	if o.ForceRecreate {
+		parts = append(parts, "recréation forcée")
+	}
+	if len(parts) == 0 {
+		return "build + up"
+	}
+	return strings.Join(parts, ", ")
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("erreur lors de la construction: %w", err)
+// StartProject démarre un projet (pull, build puis up selon les options)
+func (m *Manager) StartProject(ctx context.Context, p *project.Project, opts StartOptions) error {
+	// Vérifier les ports avant un build qui peut durer plusieurs minutes
+	if err := m.checkPorts(ctx, p, opts); err != nil {
+		return err
 	}
 
-	fmt.Printf("🚀 Démarrage du projet %s...\n", p.Name)
-	cmd = exec.Command("docker-compose", "-f", "docker-compose.yml", "-p", p.Name, "up", "-d")
-	cmd.Dir = p.Path
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if opts.Pull {
+		m.report(p, "pull", false, "📥 Mise à jour des images %s...", p.Name)
+		args := append([]string{"pull", "--ignore-pull-failures"}, opts.Services...)
+		if err := m.compose(ctx, p, args...); err != nil {
+			return fmt.Errorf("erreur lors du pull: %w", err)
+		}
+	}
 
-	if err := cmd.Run(); err != nil {
+	if !opts.NoBuild {
+		m.report(p, "build", false, "🔨 Construction de l'image %s...", p.Name)
+		args := []string{"build"}
+		if opts.NoCache {
+			args = append(args, "--no-cache")
+		}
+		if err := m.compose(ctx, p, append(args, opts.Services...)...); err != nil {
+			return fmt.Errorf("erreur lors de la construction: %w", err)
+		}
+	}
+
+	m.report(p, "up", false, "🚀 Démarrage du projet %s...", p.Name)
+	args := []string{"up", "-d"}
+	if opts.NoBuild {
+		args = append(args, "--no-build")
+	}
+	if opts.ForceRecreate {
+		args = append(args, "--force-recreate")
+	}
+	if err := m.compose(ctx, p, append(args, opts.Services...)...); err != nil {
 		return fmt.Errorf("erreur lors du démarrage: %w", err)
 	}
 
-	fmt.Printf("✅ Projet %s démarré avec succès\n", p.Name)
+	if opts.Wait {
+		if err := m.WaitHealthy(ctx, p, opts.waitOptions()); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Services) > 0 {
+		m.report(p, "up", true, "✅ Services %s du projet %s démarrés avec succès", strings.Join(opts.Services, ", "), p.Name)
+		return nil
+	}
+	m.report(p, "up", true, "✅ Projet %s démarré avec succès", p.Name)
+	return nil
+}
+
+// checkPorts refuse le démarrage si des ports publiés sont déjà utilisés,
+// ou se contente d'avertir avec IgnorePortConflicts
+func (m *Manager) checkPorts(ctx context.Context, p *project.Project, opts StartOptions) error {
+	conflicts, err := m.CheckPortConflicts(ctx, p, opts.Services)
+	if err != nil {
+		if ctx.Err() != nil {
+			return &StepError{Step: "ports", Err: ctx.Err()}
+		}
+		m.report(p, "ports", true, "⚠️  Vérification des ports impossible: %v", err)
+		return nil
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	conflictErr := &PortConflictError{Project: p.Name, Conflicts: conflicts}
+	if !opts.IgnorePortConflicts {
+		return conflictErr
+	}
+	for _, conflict := range conflicts {
+		m.report(p, "ports", true, "⚠️  Port %s (service %s) déjà utilisé par %s", conflict.Port, conflict.Port.Service, conflict.Holder)
+	}
+	return nil
+}
+
+// StopOptions contrôle StopProject
+type StopOptions struct {
+	Services []string // services à arrêter (tout le projet si vide)
+	// Remove supprime les containers après l'arrêt. Pour le projet entier
+	// cela correspond à down ; sinon seul stop est exécuté.
+	Remove bool
+}
+
+// StopProject arrête le projet ou certains services, et supprime les containers si demandé
+func (m *Manager) StopProject(ctx context.Context, p *project.Project, opts StopOptions) error {
+	affected := m.affectedContainers(ctx, p, opts.Services)
+
+	if len(opts.Services) == 0 {
+		step := "stop"
+		if opts.Remove {
+			step = "down"
+		}
+		m.report(p, step, false, "🛑 Arrêt du projet %s...", p.Name)
+		if err := m.compose(ctx, p, step); err != nil {
+			return fmt.Errorf("erreur lors de l'arrêt: %w", err)
+		}
+	} else {
+		m.report(p, "stop", false, "🛑 Arrêt des services %s du projet %s...", strings.Join(opts.Services, ", "), p.Name)
+		if err := m.compose(ctx, p, append([]string{"stop"}, opts.Services...)...); err != nil {
+			return fmt.Errorf("erreur lors de l'arrêt: %w", err)
+		}
+		if opts.Remove {
+			if err := m.compose(ctx, p, append([]string{"rm", "-f"}, opts.Services...)...); err != nil {
+				return fmt.Errorf("erreur lors de la suppression des containers: %w", err)
+			}
+		}
+	}
+
+	action := "arrêtés"
+	if opts.Remove {
+		action = "arrêtés et supprimés"
+	}
+	m.report(p, "stop", true, "✅ Containers %s : %s", action, describeContainers(affected))
 	return nil
 }
 
-// StopProject arrête et supprime les containers
-func (m *Manager) StopProject(p *project.Project) error {
-	fmt.Printf("🛑 Arrêt du projet %s...\n", p.Name)
-	cmd := exec.Command("docker-compose", "-f", "docker-compose.yml", "-p", p.Name, "down")
-	cmd.Dir = p.Path
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// RestartOptions contrôle RestartProject
+type RestartOptions struct {
+	Services []string // services à redémarrer (tout le projet si vide)
+	// Recreate supprime puis recrée les containers (down/up) au lieu d'un simple restart
+	Recreate bool
+	// Build reconstruit les images avant de recréer (avec Recreate uniquement)
+	Build bool
+	// Wait attend que les services soient opérationnels après le redémarrage
+	Wait         bool
+	WaitTimeout  time.Duration
+	HealthChecks map[string]string
+}
+
+// RestartProject redémarre tout le projet ou certains services.
+// Sans Recreate, les containers existants sont simplement redémarrés.
+func (m *Manager) RestartProject(ctx context.Context, p *project.Project, opts RestartOptions) error {
+	target := "du projet " + p.Name
+	if len(opts.Services) > 0 {
+		target = fmt.Sprintf("des services %s du projet %s", strings.Join(opts.Services, ", "), p.Name)
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("erreur lors de l'arrêt: %w", err)
+	if !opts.Recreate {
+		affected := m.affectedContainers(ctx, p, opts.Services)
+		m.report(p, "restart", false, "🔄 Redémarrage %s...", target)
+		if err := m.compose(ctx, p, append([]string{"restart"}, opts.Services...)...); err != nil {
+			return fmt.Errorf("erreur lors du redémarrage: %w", err)
+		}
+		m.report(p, "restart", true, "✅ Containers redémarrés : %s", describeContainers(affected))
+		if opts.Wait {
+			restarted := make(map[string]time.Time, len(affected))
+			for _, service := range affected {
+				if !service.StartedAt.IsZero() {
+					restarted[service.Container] = service.StartedAt
+				}
+			}
+			return m.WaitHealthy(ctx, p, WaitOptions{Services: opts.Services, Timeout: opts.WaitTimeout, Checks: opts.HealthChecks, Restarted: restarted})
+		}
+		return nil
 	}
 
-	fmt.Printf("✅ Projet %s arrêté et conteneurs supprimés\n", p.Name)
+	m.report(p, "restart", false, "♻️  Recréation %s...", target)
+	if err := m.StopProject(ctx, p, StopOptions{Services: opts.Services, Remove: true}); err != nil {
+		return err
+	}
+	startOpts := StartOptions{
+		NoBuild:      !opts.Build,
+		Services:     opts.Services,
+		Wait:         opts.Wait,
+		WaitTimeout:  opts.WaitTimeout,
+		HealthChecks: opts.HealthChecks,
+	}
+	if err := m.StartProject(ctx, p, startOpts); err != nil {
+		return err
+	}
+
+	recreated := m.affectedContainers(ctx, p, opts.Services)
+	m.report(p, "restart", true, "✅ Containers recréés : %s", describeContainers(recreated))
 	return nil
 }
 
 // RestartService redémarre un service (rapide, sans rebuild)
-func (m *Manager) RestartService(p *project.Project, serviceName string) error {
-	fmt.Printf("🔄 Redémarrage du service %s du projet %s...\n", serviceName, p.Name)
-	cmd := exec.Command("docker-compose", "-f", "docker-compose.yml", "-p", p.Name, "restart", serviceName)
-	cmd.Dir = p.Path
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+func (m *Manager) RestartService(ctx context.Context, p *project.Project, serviceName string) error {
+	var services []string
+	if serviceName != "" {
+		services = []string{serviceName}
+	}
+	return m.RestartProject(ctx, p, RestartOptions{Services: services})
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("erreur lors du redémarrage: %w", err)
+// affectedContainers retourne les containers actifs des services visés (tous si vide)
+func (m *Manager) affectedContainers(ctx context.Context, p *project.Project, services []string) []project.Service {
+	statuses, err := m.GetServiceStatuses(ctx, p)
+	if err != nil {
+		return nil
 	}
 
-	fmt.Printf("✅ Service %s redémarré avec succès\n", serviceName)
-	return nil
+	wanted := make(map[string]bool, len(services))
+	for _, service := range services {
+		wanted[service] = true
+	}
+
+	var affected []project.Service
+	for _, status := range statuses {
+		if status.Container == "" || status.Status != "running" {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[status.Name] {
+			continue
+		}
+		affected = append(affected, status)
+	}
+	return affected
+}
+
+// describeContainers liste les containers sous la forme "api (a1b2c3d4e5f6), db (...)"
+func describeContainers(containers []project.Service) string {
+	if len(containers) == 0 {
+		return "aucun container actif"
+	}
+
+	parts := make([]string, 0, len(containers))
+	for _, container := range containers {
+		parts = append(parts, fmt.Sprintf("%s (%s)", container.Name, container.Container))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // GetStatus récupère le statut d'un projet
 // Retourne: (running, containerCount, detailedError)
-func (m *Manager) GetStatus(p *project.Project) (bool, int, error) {
-	cmd := exec.Command("docker-compose", "-f", "docker-compose.yml", "-p", p.Name, "ps", "-q")
-	cmd.Dir = p.Path
+func (m *Manager) GetStatus(ctx context.Context, p *project.Project) (bool, int, error) {
+	if containers, err := m.engineContainers(ctx, p, false); err == nil {
+		return len(containers) > 0, len(containers), nil
+	}
 
-	output, err := cmd.Output()
+	output, err := m.composeOutput(ctx, p, "ps", "-q")
 	if err != nil {
 		// Ne pas retourner d'erreur - juste indiquer "not ready"
 		// Cela signifie que docker-compose.yml manque ou la config est cassée
 		return false, 0, nil
 	}
 
-	containers := strings.Count(strings.TrimSpace(string(output)), "\n") + 1
-	if strings.TrimSpace(string(output)) == "" {
-		containers = 0
-	}
-
-	running := containers > 0 && strings.TrimSpace(string(output)) != ""
-
-	return running, containers, nil
+	containers := countLines(output)
+	return containers > 0, containers, nil
 }
 
 // GetStatusDetailed récupère le statut détaillé avec des informations d'erreur
-func (m *Manager) GetStatusDetailed(p *project.Project) (bool, int, string) {
-	cmd := exec.Command("docker-compose", "-f", "docker-compose.yml", "-p", p.Name, "ps", "-q")
-	cmd.Dir = p.Path
-
-	var stderr strings.Builder
-	cmd.Stderr = &stderr
-
-	output, err := cmd.Output()
-	if err != nil {
-		errMsg := stderr.String()
-		if errMsg == "" {
-			errMsg = err.Error()
+func (m *Manager) GetStatusDetailed(ctx context.Context, p *project.Project) (bool, int, string) {
+	var containers int
+	if list, err := m.engineContainers(ctx, p, false); err == nil {
+		containers = len(list)
+	} else {
+		output, err := m.composeOutput(ctx, p, "ps", "-q")
+		if err != nil {
+			return false, 0, err.Error()
 		}
-		return false, 0, errMsg
-	}
-
-	containers := strings.Count(strings.TrimSpace(string(output)), "\n") + 1
-	if strings.TrimSpace(string(output)) == "" {
-		containers = 0
+		containers = countLines(output)
 	}
 
-	running := containers > 0 && strings.TrimSpace(string(output)) != ""
+	running := containers > 0
 
 	statusMsg := "Arrêté"
 	if running {
@@ -135,9 +503,33 @@ func (m *Manager) GetStatusDetailed(p *project.Project) (bool, int, string) {
 	return running, containers, statusMsg
 }
 
+// LoadStatuses renseigne Running et ServiceCount pour chaque projet.
+// Avec l'API Engine, un seul appel suffit pour l'ensemble des projets (seuls
+// les projets avec profils interrogent compose pour leurs services actifs) ;
+// sinon les projets sont interrogés en parallèle (voir eachProject).
+func (m *Manager) LoadStatuses(ctx context.Context, projects []project.Project) {
+	if m.Engine != nil {
+		queryCtx, cancel := withTimeout(ctx, m.Timeouts.Query)
+		byProject, err := m.Engine.ContainersByProject(queryCtx, false)
+		cancel()
+		if err == nil {
+			m.eachProject(ctx, projects, func(ctx context.Context, p *project.Project) {
+				count := len(activeContainers(serviceContainers(byProject[p.Name]), m.activeServices(ctx, p)))
+				p.Running = count > 0
+				p.ServiceCount = count
+			})
+			return
+		}
+	}
+
+	m.eachProject(ctx, projects, func(ctx context.Context, p *project.Project) {
+		p.Running, p.ServiceCount, _ = m.GetStatus(ctx, p)
+	})
+}
+
 // GetLogs récupère les logs d'un projet
-func (m *Manager) GetLogs(p *project.Project, serviceName string, follow bool) error {
-	args := []string{"-f", "docker-compose.yml", "-p", p.Name, "logs"}
+func (m *Manager) GetLogs(ctx context.Context, p *project.Project, serviceName string, follow bool) error {
+	args := []string{"logs"}
 	if follow {
 		args = append(args, "-f")
 	}
@@ -145,21 +537,21 @@ func (m *Manager) GetLogs(p *project.Project, serviceName string, follow bool) e
 		args = append(args, serviceName)
 	}
 
-	cmd := exec.Command("docker-compose", args...)
-	cmd.Dir = p.Path
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	// Pas de timeout : les logs suivis (-f) tournent jusqu'à l'annulation
+	inv := m.invocation(p, args...)
+	inv.Stdin = m.Stdin
+	inv.Stdout = m.Stdout
+	inv.Stderr = m.Stderr
+	err := m.Runner.Run(ctx, inv)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
 }
 
 // GetServices retourne la liste des services d'un projet
-func (m *Manager) GetServices(p *project.Project) ([]string, error) {
-	cmd := exec.Command("docker-compose", "-f", "docker-compose.yml", "config", "--services")
-	cmd.Dir = p.Path
-
-	output, err := cmd.Output()
+func (m *Manager) GetServices(ctx context.Context, p *project.Project) ([]string, error) {
+	output, err := m.composeOutput(ctx, p, "config", "--services")
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la récupération des services: %w", err)
 	}
@@ -168,58 +560,42 @@ func (m *Manager) GetServices(p *project.Project) ([]string, error) {
 	return services, nil
 }
 
-// GetServiceURLs retourne une map service -> urls locales exposees
-func (m *Manager) GetServiceURLs(p *project.Project) (map[string][]string, error) {
-	cmd := exec.Command(
-		"docker",
-		"ps",
-		"--filter",
-		fmt.Sprintf("label=com.docker.compose.project=%s", p.Name),
-		"--format",
-		"{{.Label \"com.docker.compose.service\"}}\t{{.Ports}}",
-	)
-	cmd.Dir = p.Path
-
-	output, err := cmd.Output()
+// GetProfiles retourne tous les profils déclarés dans les fichiers compose
+func (m *Manager) GetProfiles(ctx context.Context, p *project.Project) ([]string, error) {
+	output, err := m.composeOutput(ctx, p, "config", "--profiles")
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la recuperation des ports: %w", err)
+		return nil, fmt.Errorf("erreur lors de la récupération des profils: %w", err)
 	}
 
-	urlsByService := make(map[string][]string)
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		parts := strings.SplitN(line, "\t", 2)
-		serviceName := strings.TrimSpace(parts[0])
-		ports := ""
-		if len(parts) > 1 {
-			ports = parts[1]
-		}
-
-		urls := portsToURLs(ports)
-		if len(urls) == 0 {
-			continue
-		}
+	return strings.Fields(strings.TrimSpace(string(output))), nil
+}
 
-		urlsByService[serviceName] = appendUnique(urlsByService[serviceName], urls)
+// countLines compte les lignes non vides d'une sortie de commande
+func countLines(output []byte) int {
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return 0
 	}
+	return strings.Count(trimmed, "\n") + 1
+}
 
-	return urlsByService, nil
+// portBinding décrit un port publié sur l'hôte
+type portBinding struct {
+	HostIP        string
+	HostPort      string
+	ContainerPort string
+	Proto         string
 }
 
-func portsToURLs(ports string) []string {
+// parsePorts lit la colonne Ports de docker ps
+// (ex: "0.0.0.0:8080->80/tcp, :::8080->80/tcp")
+func parsePorts(ports string) []portBinding {
 	ports = strings.TrimSpace(ports)
 	if ports == "" {
 		return nil
 	}
 
-	var urls []string
-	seen := make(map[string]struct{})
-
+	var bindings []portBinding
 	for _, segment := range strings.Split(ports, ",") {
 		segment = strings.TrimSpace(segment)
 		if !strings.Contains(segment, "->") {
@@ -237,15 +613,37 @@ func portsToURLs(ports string) []string {
 			continue
 		}
 
-		url := fmt.Sprintf("http://localhost:%s", port)
-		if _, exists := seen[url]; exists {
-			continue
+		containerPort, proto := strings.TrimSpace(parts[1]), "tcp"
+		if idx := strings.Index(containerPort, "/"); idx != -1 {
+			containerPort, proto = containerPort[:idx], containerPort[idx+1:]
 		}
-		seen[url] = struct{}{}
-		urls = append(urls, url)
+
+		bindings = append(bindings, portBinding{
+			HostIP:        strings.Trim(strings.TrimSuffix(hostPart, ":"+port), "[]"),
+			HostPort:      port,
+			ContainerPort: containerPort,
+			Proto:         proto,
+		})
 	}
 
-	return urls
+	return bindings
+}
+
+// engineBindings convertit les ports de l'API Engine
+func engineBindings(ports []Port) []portBinding {
+	var bindings []portBinding
+	for _, port := range ports {
+		if port.PublicPort == 0 {
+			continue
+		}
+		bindings = append(bindings, portBinding{
+			HostIP:        port.IP,
+			HostPort:      strconv.Itoa(port.PublicPort),
+			ContainerPort: strconv.Itoa(port.PrivatePort),
+			Proto:         port.Type,
+		})
+	}
+	return bindings
 }
 
 func extractHostPort(hostPart string) string {
@@ -263,31 +661,17 @@ func extractHostPort(hostPart string) string {
 	return port
 }
 
-func appendUnique(existing []string, values []string) []string {
-	seen := make(map[string]struct{}, len(existing))
-	for _, value := range existing {
-		seen[value] = struct{}{}
-	}
-
-	for _, value := range values {
-		if _, ok := seen[value]; ok {
-			continue
-		}
-		seen[value] = struct{}{}
-		existing = append(existing, value)
-	}
-
-	return existing
-}
-
 // EnsureDockerRunning vérifie que Docker est accessible
-func EnsureDockerRunning() error {
-	installed, _ := CheckDockerInstallation()
+func (m *Manager) EnsureDockerRunning(ctx context.Context) error {
+	installed, _ := m.CheckDockerInstallation(ctx)
 	if !installed {
 		return fmt.Errorf("❌ Docker n'est pas installé.\n📖 Visitez: https://www.docker.com/products/docker-desktop")
 	}
 
-	running, _ := CheckDockerDaemonStatus()
+	running, err := m.CheckDockerDaemonStatus(ctx)
+	if err != nil {
+		return err
+	}
 	if !running {
 		return fmt.Errorf("⏹️  Docker daemon est arrêté.\nUsez: docker-manager daemon start")
 	}
@@ -295,36 +679,47 @@ func EnsureDockerRunning() error {
 }
 
 // CheckDockerInstallation vérifie si Docker est installé
-func CheckDockerInstallation() (bool, error) {
-	cmd := exec.Command("docker", "--version")
+func (m *Manager) CheckDockerInstallation(ctx context.Context) (bool, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Daemon)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "docker", "--version")
 	err := cmd.Run()
 	return err == nil, nil
 }
 
-// CheckDockerDaemonStatus vérifie si le daemon Docker est actif
-func CheckDockerDaemonStatus() (bool, error) {
-	cmd := exec.Command("docker", "info")
+// CheckDockerDaemonStatus vérifie si le daemon Docker est actif.
+// Un daemon qui ne répond pas dans le délai est considéré comme arrêté ;
+// seule une annulation (Ctrl-C) est retournée comme erreur.
+func (m *Manager) CheckDockerDaemonStatus(ctx context.Context) (bool, error) {
+	checkCtx, cancel := withTimeout(ctx, m.Timeouts.Daemon)
+	defer cancel()
+
+	cmd := exec.CommandContext(checkCtx, "docker", "info")
 	err := cmd.Run()
+	if ctx.Err() != nil {
+		return false, &StepError{Step: "docker info", Err: ctx.Err()}
+	}
 	return err == nil, nil
 }
 
 // StartDockerDaemon démarre Docker
-func StartDockerDaemon() error {
+func StartDockerDaemon(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
 		// macOS: ouvrir Docker.app
-		cmd := exec.Command("open", "-a", "Docker")
+		cmd := exec.CommandContext(ctx, "open", "-a", "Docker")
 		return cmd.Run()
 	case "linux":
 		// Linux: systemctl start docker
-		cmd := exec.Command("sudo", "systemctl", "start", "docker")
+		cmd := exec.CommandContext(ctx, "sudo", "systemctl", "start", "docker")
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		cmd.Stdin = os.Stdin
 		return cmd.Run()
 	case "windows":
 		// Windows: PowerShell
-		cmd := exec.Command("powershell", "-Command", "Start-Process Docker")
+		cmd := exec.CommandContext(ctx, "powershell", "-Command", "Start-Process Docker")
 		return cmd.Run()
 	default:
 		return fmt.Errorf("système d'exploitation non supporté")
@@ -332,28 +727,28 @@ func StartDockerDaemon() error {
 }
 
 // StopDockerDaemon arrête Docker
-func StopDockerDaemon() error {
+func StopDockerDaemon(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
 		// macOS: quit application Docker Desktop (syntaxe osascript correcte)
-		cmd := exec.Command("osascript", "-e", "quit application \"Docker Desktop\"")
+		cmd := exec.CommandContext(ctx, "osascript", "-e", "quit application \"Docker Desktop\"")
 		err := cmd.Run()
 		if err != nil {
 			// Fallback: utiliser killall si osascript échoue
-			killCmd := exec.Command("killall", "Docker")
+			killCmd := exec.CommandContext(ctx, "killall", "Docker")
 			return killCmd.Run()
 		}
 		return nil
 	case "linux":
 		// Linux: systemctl stop docker
-		cmd := exec.Command("sudo", "systemctl", "stop", "docker")
+		cmd := exec.CommandContext(ctx, "sudo", "systemctl", "stop", "docker")
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		cmd.Stdin = os.Stdin
 		return cmd.Run()
 	case "windows":
 		// Windows: PowerShell
-		cmd := exec.Command("powershell", "-Command", "Stop-Process -Name Docker.exe")
+		cmd := exec.CommandContext(ctx, "powershell", "-Command", "Stop-Process -Name Docker.exe")
 		return cmd.Run()
 	default:
 		return fmt.Errorf("système d'exploitation non supporté")
@@ -0,0 +1,190 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// testProject est un projet avec plusieurs fichiers compose, un profil,
+// un fichier d'environnement et une variable injectée
+func testProject() *project.Project {
+	return &project.Project{
+		Name:         "web",
+		Path:         "/srv/docker-web",
+		ComposeFiles: []string{"docker-compose.yml", "docker-compose.override.yml"},
+		Profiles:     []string{"debug"},
+		EnvFiles:     []string{".env.local"},
+		Env:          map[string]string{"APP_ENV": "test"},
+	}
+}
+
+// testBase est le préfixe compose de testProject
+var testBase = []string{
+	"-f", "docker-compose.yml", "-f", "docker-compose.override.yml",
+	"-p", "web", "--profile", "debug", "--env-file", ".env.local",
+}
+
+// emptyEngine répond qu'aucun container n'existe
+func emptyEngine(t *testing.T) *EngineClient {
+	return newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Container{})
+	}))
+}
+
+// newTestManager crée un Manager dont les commandes compose sont enregistrées
+func newTestManager(t *testing.T) (*Manager, *fakeRunner) {
+	fake := &fakeRunner{}
+	return &Manager{
+		Runner: fake.runner(),
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+		Engine: emptyEngine(t),
+	}, fake
+}
+
+func TestStartProjectArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts StartOptions
+		want [][]string
+	}{
+		{
+			name: "build + up",
+			want: [][]string{{"build"}, {"up", "-d"}},
+		},
+		{
+			name: "no-build",
+			opts: StartOptions{NoBuild: true},
+			want: [][]string{{"up", "-d", "--no-build"}},
+		},
+		{
+			name: "pull, no-cache, services",
+			opts: StartOptions{Pull: true, NoCache: true, ForceRecreate: true, Services: []string{"db", "app"}},
+			want: [][]string{
+				{"pull", "--ignore-pull-failures", "db", "app"},
+				{"build", "--no-cache", "db", "app"},
+				{"up", "-d", "--force-recreate", "db", "app"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, fake := newTestManager(t)
+			if err := mgr.StartProject(context.Background(), testProject(), tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := fake.commands(t, testBase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandes = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartProjectInvocation(t *testing.T) {
+	mgr, fake := newTestManager(t)
+	if err := mgr.StartProject(context.Background(), testProject(), StartOptions{NoBuild: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	inv := fake.calls[len(fake.calls)-1]
+	if inv.Dir != "/srv/docker-web" {
+		t.Errorf("Dir = %q", inv.Dir)
+	}
+	if !reflect.DeepEqual(inv.Env, []string{"APP_ENV=test"}) {
+		t.Errorf("Env = %q", inv.Env)
+	}
+}
+
+func TestStartOptionsDescribe(t *testing.T) {
+	tests := []struct {
+		opts StartOptions
+		want string
+	}{
+		{StartOptions{}, "build + up"},
+		{StartOptions{NoBuild: true}, "sans build"},
+		{StartOptions{Pull: true, NoCache: true}, "pull, build sans cache"},
+		{StartOptions{Pull: true, NoBuild: true, ForceRecreate: true}, "pull, sans build, recréation forcée"},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.Describe(); got != tt.want {
+			t.Errorf("%+v : %q, attendu %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestStopProjectArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts StopOptions
+		want [][]string
+	}{
+		{name: "down", opts: StopOptions{Remove: true}, want: [][]string{{"down"}}},
+		{name: "keep", opts: StopOptions{}, want: [][]string{{"stop"}}},
+		{
+			name: "services --rm",
+			opts: StopOptions{Services: []string{"worker"}, Remove: true},
+			want: [][]string{{"stop", "worker"}, {"rm", "-f", "worker"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, fake := newTestManager(t)
+			if err := mgr.StopProject(context.Background(), testProject(), tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := fake.commands(t, testBase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandes = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartProjectArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RestartOptions
+		want [][]string
+	}{
+		{name: "restart", want: [][]string{{"restart"}}},
+		{
+			name: "restart services",
+			opts: RestartOptions{Services: []string{"nginx"}},
+			want: [][]string{{"restart", "nginx"}},
+		},
+		{
+			name: "recreate",
+			opts: RestartOptions{Recreate: true},
+			want: [][]string{{"down"}, {"up", "-d", "--no-build"}},
+		},
+		{
+			name: "recreate + build",
+			opts: RestartOptions{Recreate: true, Build: true},
+			want: [][]string{{"down"}, {"build"}, {"up", "-d"}},
+		},
+		{
+			name: "recreate services",
+			opts: RestartOptions{Recreate: true, Services: []string{"app"}},
+			want: [][]string{{"stop", "app"}, {"rm", "-f", "app"}, {"up", "-d", "--no-build", "app"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, fake := newTestManager(t)
+			if err := mgr.RestartProject(context.Background(), testProject(), tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := fake.commands(t, testBase); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandes = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
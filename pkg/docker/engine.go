@@ -0,0 +1,503 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Labels posés par docker compose sur les containers et les volumes
+const (
+	LabelProject = "com.docker.compose.project"
+	LabelService = "com.docker.compose.service"
+	LabelVolume  = "com.docker.compose.volume"
+	LabelOneOff  = "com.docker.compose.oneoff"
+)
+
+// EngineClient interroge l'API Docker Engine directement (socket unix ou tcp)
+type EngineClient struct {
+	Host    string
+	baseURL string
+	http    *http.Client
+}
+
+// Port décrit un port exposé tel que retourné par /containers/json
+type Port struct {
+	IP          string `json:"IP"`
+	PrivatePort int    `json:"PrivatePort"`
+	PublicPort  int    `json:"PublicPort"`
+	Type        string `json:"Type"`
+}
+
+// Container est le résumé d'un container retourné par /containers/json
+type Container struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Created int64             `json:"Created"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []Port            `json:"Ports"`
+}
+
+// Project retourne le projet compose du container
+func (c Container) Project() string {
+	return c.Labels[LabelProject]
+}
+
+// Service retourne le service compose du container
+func (c Container) Service() string {
+	return c.Labels[LabelService]
+}
+
+// OneOff indique un container lancé par compose run (pas un service du projet)
+func (c Container) OneOff() bool {
+	return strings.EqualFold(c.Labels[LabelOneOff], "true")
+}
+
+// Running indique si le container est démarré
+func (c Container) Running() bool {
+	return c.State == "running"
+}
+
+// NewEngineClient crée un client selon DOCKER_HOST ou le socket par défaut
+func NewEngineClient() (*EngineClient, error) {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return NewEngineClientForHost(host)
+	}
+
+	for _, socket := range defaultSockets() {
+		if _, err := os.Stat(socket); err == nil {
+			return NewEngineClientForSocket(socket), nil
+		}
+	}
+	return nil, fmt.Errorf("aucun socket Docker trouvé")
+}
+
+// NewEngineClientForHost crée un client pour une adresse unix:// ou tcp://
+func NewEngineClientForHost(host string) (*EngineClient, error) {
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		return NewEngineClientForSocket(strings.TrimPrefix(host, "unix://")), nil
+	case strings.HasPrefix(host, "tcp://"):
+		address := strings.TrimPrefix(host, "tcp://")
+		return &EngineClient{
+			Host:    host,
+			baseURL: "http://" + address,
+			http:    &http.Client{},
+		}, nil
+	default:
+		return nil, fmt.Errorf("DOCKER_HOST non supporté: %s", host)
+	}
+}
+
+// NewEngineClientForSocket crée un client parlant HTTP sur un socket unix
+func NewEngineClientForSocket(path string) *EngineClient {
+	dialer := &net.Dialer{Timeout: 2 * time.Second}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		},
+	}
+
+	return &EngineClient{
+		Host:    "unix://" + path,
+		baseURL: "http://docker",
+		http:    &http.Client{Transport: transport},
+	}
+}
+
+func defaultSockets() []string {
+	sockets := []string{"/var/run/docker.sock"}
+	if home := os.Getenv("HOME"); home != "" {
+		sockets = append(sockets, filepath.Join(home, ".docker", "run", "docker.sock"))
+	}
+	return sockets
+}
+
+// Ping vérifie que le daemon répond
+func (c *EngineClient) Ping(ctx context.Context) error {
+	return c.get(ctx, "/_ping", nil, nil)
+}
+
+// ListContainers liste les containers, filtrés selon la syntaxe de l'API
+// (ex: {"label": ["com.docker.compose.project=web"]})
+func (c *EngineClient) ListContainers(ctx context.Context, all bool, filters map[string][]string) ([]Container, error) {
+	query := url.Values{}
+	if all {
+		query.Set("all", "1")
+	}
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(encoded))
+	}
+
+	var containers []Container
+	if err := c.get(ctx, "/containers/json", query, &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// ProjectContainers liste les containers d'un projet compose
+func (c *EngineClient) ProjectContainers(ctx context.Context, projectName string, all bool) ([]Container, error) {
+	return c.ListContainers(ctx, all, map[string][]string{
+		"label": {fmt.Sprintf("%s=%s", LabelProject, projectName)},
+	})
+}
+
+// ContainersByProject récupère en un seul appel tous les containers compose,
+// regroupés par projet
+func (c *EngineClient) ContainersByProject(ctx context.Context, all bool) (map[string][]Container, error) {
+	containers, err := c.ListContainers(ctx, all, map[string][]string{
+		"label": {LabelProject},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byProject := make(map[string][]Container)
+	for _, container := range containers {
+		name := container.Project()
+		byProject[name] = append(byProject[name], container)
+	}
+	return byProject, nil
+}
+
+// get exécute un GET et décode la réponse JSON dans out (si non nil)
+func (c *EngineClient) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion au daemon Docker (%s): %w", c.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return engineError(resp)
+	}
+
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("réponse invalide de l'API Docker: %w", err)
+	}
+	return nil
+}
+
+// EngineError est une erreur retournée par l'API Docker (statut HTTP >= 300)
+type EngineError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *EngineError) Error() string {
+	return fmt.Sprintf("API Docker (%d): %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound indique si l'API a répondu que l'objet n'existe pas (404)
+func IsNotFound(err error) bool {
+	var engineErr *EngineError
+	return errors.As(err, &engineErr) && engineErr.StatusCode == http.StatusNotFound
+}
+
+// engineError extrait le message d'erreur d'une réponse de l'API
+func engineError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+		return &EngineError{StatusCode: resp.StatusCode, Message: apiErr.Message}
+	}
+	return &EngineError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+// ContainerState est l'état détaillé d'un container (/containers/{id}/json)
+type ContainerState struct {
+	Status     string        `json:"Status"`
+	Running    bool          `json:"Running"`
+	Paused     bool          `json:"Paused"`
+	Restarting bool          `json:"Restarting"`
+	OOMKilled  bool          `json:"OOMKilled"`
+	Dead       bool          `json:"Dead"`
+	ExitCode   int           `json:"ExitCode"`
+	StartedAt  string        `json:"StartedAt"`
+	FinishedAt string        `json:"FinishedAt"`
+	Health     *HealthStatus `json:"Health,omitempty"`
+}
+
+// HealthStatus est le résultat du healthcheck Docker d'un container
+type HealthStatus struct {
+	Status        string `json:"Status"`
+	FailingStreak int    `json:"FailingStreak"`
+}
+
+// PortBinding est une publication de port dans NetworkSettings.Ports
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// ContainerInfo est le détail d'un container, au format de docker inspect
+type ContainerInfo struct {
+	ID     string         `json:"Id"`
+	Name   string         `json:"Name"`
+	State  ContainerState `json:"State"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Ports map[string][]PortBinding `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+// InspectContainer retourne le détail d'un container
+func (c *EngineClient) InspectContainer(ctx context.Context, id string) (*ContainerInfo, error) {
+	var info ContainerInfo
+	if err := c.get(ctx, "/containers/"+url.PathEscape(id)+"/json", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// CPUStats est un échantillon d'utilisation CPU de /containers/{id}/stats
+type CPUStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemUsage uint64 `json:"system_cpu_usage"`
+	OnlineCPUs  int    `json:"online_cpus"`
+}
+
+// ContainerStats est un échantillon de /containers/{id}/stats?stream=0
+type ContainerStats struct {
+	CPUStats    CPUStats `json:"cpu_stats"`
+	PreCPUStats CPUStats `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IOServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+	PidsStats struct {
+		Current int `json:"current"`
+	} `json:"pids_stats"`
+}
+
+// ContainerStats retourne un échantillon de consommation d'un container.
+// Le daemon attend un second relevé CPU avant de répondre (environ 1s).
+func (c *EngineClient) ContainerStats(ctx context.Context, id string) (*ContainerStats, error) {
+	var stats ContainerStats
+	query := url.Values{"stream": {"0"}}
+	if err := c.get(ctx, "/containers/"+url.PathEscape(id)+"/stats", query, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ImageSummary est une image telle que retournée par /system/df
+type ImageSummary struct {
+	ID         string            `json:"Id"`
+	RepoTags   []string          `json:"RepoTags"`
+	Labels     map[string]string `json:"Labels"`
+	Size       int64             `json:"Size"`
+	SharedSize int64             `json:"SharedSize"`
+	Containers int64             `json:"Containers"`
+}
+
+// UniqueSize retourne la taille libérée en supprimant l'image
+// (couches non partagées avec d'autres images)
+func (i ImageSummary) UniqueSize() int64 {
+	if i.SharedSize > 0 && i.SharedSize <= i.Size {
+		return i.Size - i.SharedSize
+	}
+	return i.Size
+}
+
+// ContainerDiskUsage est un container et sa couche inscriptible (/system/df)
+type ContainerDiskUsage struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	ImageID string            `json:"ImageID"`
+	Labels  map[string]string `json:"Labels"`
+	State   string            `json:"State"`
+	SizeRw  int64             `json:"SizeRw"`
+}
+
+// Active indique un container démarré (en cours, en pause ou en redémarrage)
+func (c ContainerDiskUsage) Active() bool {
+	switch c.State {
+	case "running", "paused", "restarting":
+		return true
+	}
+	return false
+}
+
+// Volume est un volume et son occupation disque (/system/df)
+type Volume struct {
+	Name      string            `json:"Name"`
+	Labels    map[string]string `json:"Labels"`
+	UsageData *struct {
+		Size     int64 `json:"Size"`
+		RefCount int64 `json:"RefCount"`
+	} `json:"UsageData,omitempty"`
+}
+
+// Size retourne la taille du volume (0 si inconnue)
+func (v Volume) Size() int64 {
+	if v.UsageData == nil || v.UsageData.Size < 0 {
+		return 0
+	}
+	return v.UsageData.Size
+}
+
+// BuildCacheRecord est une entrée du cache de build BuildKit
+type BuildCacheRecord struct {
+	ID          string   `json:"ID"`
+	Parent      string   `json:"Parent"`  // API < 1.42
+	Parents     []string `json:"Parents"` // API >= 1.42
+	Description string   `json:"Description"`
+	InUse       bool     `json:"InUse"`
+	Shared      bool     `json:"Shared"`
+	Size        int64    `json:"Size"`
+}
+
+// parents retourne les enregistrements parents (champ Parents ou Parent)
+func (r BuildCacheRecord) parents() []string {
+	if len(r.Parents) > 0 {
+		return r.Parents
+	}
+	if r.Parent != "" {
+		return []string{r.Parent}
+	}
+	return nil
+}
+
+// SystemDiskUsage est la réponse de /system/df
+type SystemDiskUsage struct {
+	LayersSize int64                `json:"LayersSize"`
+	Images     []ImageSummary       `json:"Images"`
+	Containers []ContainerDiskUsage `json:"Containers"`
+	Volumes    []Volume             `json:"Volumes"`
+	BuildCache []BuildCacheRecord   `json:"BuildCache"`
+}
+
+// DiskUsage retourne l'occupation disque des images, containers, volumes et du cache de build
+func (c *EngineClient) DiskUsage(ctx context.Context) (*SystemDiskUsage, error) {
+	var usage SystemDiskUsage
+	if err := c.get(ctx, "/system/df", nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// Network est un réseau Docker tel que retourné par /networks
+type Network struct {
+	ID     string            `json:"Id"`
+	Name   string            `json:"Name"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// ListNetworks liste les réseaux, filtrés selon la syntaxe de l'API
+func (c *EngineClient) ListNetworks(ctx context.Context, filters map[string][]string) ([]Network, error) {
+	query := url.Values{}
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(encoded))
+	}
+
+	var networks []Network
+	if err := c.get(ctx, "/networks", query, &networks); err != nil {
+		return nil, err
+	}
+	return networks, nil
+}
+
+// ListVolumes liste les volumes, filtrés selon la syntaxe de l'API
+func (c *EngineClient) ListVolumes(ctx context.Context, filters map[string][]string) ([]Volume, error) {
+	query := url.Values{}
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(encoded))
+	}
+
+	var response struct {
+		Volumes []Volume `json:"Volumes"`
+	}
+	if err := c.get(ctx, "/volumes", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Volumes, nil
+}
+
+// Events ouvre le flux /events de l'API Engine (un objet JSON par événement).
+// Le flux reste ouvert jusqu'à l'annulation du contexte.
+func (c *EngineClient) Events(ctx context.Context, filters map[string][]string) (io.ReadCloser, error) {
+	query := url.Values{}
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(encoded))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/events?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion au daemon Docker (%s): %w", c.Host, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, engineError(resp)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,160 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// newFakeEngine démarre un faux daemon Docker sur un socket unix temporaire
+func newFakeEngine(t *testing.T, handler http.Handler) *EngineClient {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("socket unix indisponible: %v", err)
+	}
+
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return NewEngineClientForSocket(socket)
+}
+
+func TestListContainersFilters(t *testing.T) {
+	var gotAll, gotFilters string
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/json" {
+			http.NotFound(w, r)
+			return
+		}
+		gotAll = r.URL.Query().Get("all")
+		gotFilters = r.URL.Query().Get("filters")
+		fmt.Fprint(w, `[{"Id":"abc","State":"running","Labels":{"com.docker.compose.project":"web","com.docker.compose.service":"app"}}]`)
+	}))
+
+	containers, err := engine.ProjectContainers(context.Background(), "web", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotAll != "1" {
+		t.Errorf("all = %q, attendu 1", gotAll)
+	}
+	var filters map[string][]string
+	if err := json.Unmarshal([]byte(gotFilters), &filters); err != nil {
+		t.Fatalf("filters invalide %q: %v", gotFilters, err)
+	}
+	if want := []string{LabelProject + "=web"}; !reflect.DeepEqual(filters["label"], want) {
+		t.Errorf("filtre label = %q, attendu %q", filters["label"], want)
+	}
+
+	if len(containers) != 1 || containers[0].Service() != "app" || !containers[0].Running() {
+		t.Errorf("containers = %+v", containers)
+	}
+}
+
+func TestContainersByProject(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Container{
+			{ID: "1", Labels: map[string]string{LabelProject: "web", LabelService: "app"}},
+			{ID: "2", Labels: map[string]string{LabelProject: "blog", LabelService: "db"}},
+			{ID: "3", Labels: map[string]string{LabelProject: "web", LabelService: "db"}},
+		})
+	}))
+
+	byProject, err := engine.ContainersByProject(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string][]string)
+	for name, containers := range byProject {
+		for _, container := range containers {
+			got[name] = append(got[name], container.ID)
+		}
+		sort.Strings(got[name])
+	}
+	want := map[string][]string{"web": {"1", "3"}, "blog": {"2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("regroupement = %v, attendu %v", got, want)
+	}
+}
+
+func TestNewEngineClientForHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantURL string
+	}{
+		{"unix:///var/run/docker.sock", "http://docker"},
+		{"tcp://127.0.0.1:2375", "http://127.0.0.1:2375"},
+	}
+	for _, tt := range tests {
+		client, err := NewEngineClientForHost(tt.host)
+		if err != nil || client.baseURL != tt.wantURL || client.Host != tt.host {
+			t.Errorf("NewEngineClientForHost(%q) = %+v, %v", tt.host, client, err)
+		}
+	}
+
+	if _, err := NewEngineClientForHost("ssh://user@host"); err == nil {
+		t.Error("ssh:// : erreur attendue")
+	}
+}
+
+func TestStatusThroughEngine(t *testing.T) {
+	var requests int32
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode([]Container{
+			{ID: "1", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "app"}},
+			{ID: "2", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "db"}},
+			{ID: "3", State: "running", Labels: map[string]string{LabelProject: "blog", LabelService: "app"}},
+		})
+	}))
+	mgr := &Manager{Engine: engine}
+
+	projects := []project.Project{{Name: "web"}, {Name: "blog"}, {Name: "shop"}}
+	mgr.LoadStatuses(context.Background(), projects)
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("%d requêtes à l'API, attendu 1 pour tous les projets", got)
+	}
+	for i, want := range []int{2, 1, 0} {
+		if projects[i].ServiceCount != want || projects[i].Running != (want > 0) {
+			t.Errorf("%s : %d containers, running=%v, attendu %d", projects[i].Name, projects[i].ServiceCount, projects[i].Running, want)
+		}
+	}
+}
+
+func TestEngineErrorMessage(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"No such container: abc"}`)
+	}))
+
+	_, err := engine.InspectContainer(context.Background(), "abc")
+	if err == nil || !strings.Contains(err.Error(), "(404): No such container: abc") {
+		t.Errorf("erreur = %v", err)
+	}
+}
+
+func TestMissingSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "absent.sock")
+	engine := NewEngineClientForSocket(socket)
+
+	if _, err := engine.ListContainers(context.Background(), false, nil); err == nil || !strings.Contains(err.Error(), "erreur de connexion au daemon Docker (unix://"+socket+")") {
+		t.Errorf("ListContainers: erreur = %v", err)
+	}
+	if _, err := engine.Events(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "erreur de connexion") {
+		t.Errorf("Events: erreur = %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType est le type d'événement container publié par l'EventHub
+type EventType string
+
+const (
+	EventCreate  EventType = "create"
+	EventStart   EventType = "start"
+	EventRestart EventType = "restart"
+	EventStop    EventType = "stop"
+	EventKill    EventType = "kill"
+	EventDie     EventType = "die"
+	EventOOM     EventType = "oom"
+	EventPause   EventType = "pause"
+	EventUnpause EventType = "unpause"
+	EventDestroy EventType = "destroy"
+	EventHealth  EventType = "health_status"
+)
+
+// watchedEvents sont les actions Docker relayées aux abonnés
+var watchedEvents = []EventType{
+	EventCreate, EventStart, EventRestart, EventStop, EventKill, EventDie,
+	EventOOM, EventPause, EventUnpause, EventDestroy, EventHealth,
+}
+
+// Event est un changement d'état d'un container compose
+type Event struct {
+	Type      EventType
+	Project   string
+	Service   string
+	Container string
+	Health    string // healthy, unhealthy, starting (EventHealth uniquement)
+	ExitCode  int    // code de sortie (EventDie uniquement)
+	Time      time.Time
+}
+
+// String retourne une description courte (ex: "web/worker die (137)")
+func (e Event) String() string {
+	text := e.Project + "/" + e.Service + " " + string(e.Type)
+	switch e.Type {
+	case EventDie:
+		text += " (" + strconv.Itoa(e.ExitCode) + ")"
+	case EventHealth:
+		text += ": " + e.Health
+	}
+	return text
+}
+
+// rawEvent est le format JSON commun à l'API /events et à docker events --format '{{json .}}'
+type rawEvent struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Actor  struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+	TimeNano int64 `json:"timeNano"`
+}
+
+// toEvent convertit un événement brut ; ok est faux pour les actions ignorées
+func (r rawEvent) toEvent() (Event, bool) {
+	action := r.Action
+	health := ""
+	// Les événements de santé arrivent sous la forme "health_status: healthy"
+	if strings.HasPrefix(action, string(EventHealth)+":") {
+		health = strings.TrimSpace(strings.TrimPrefix(action, string(EventHealth)+":"))
+		action = string(EventHealth)
+	}
+
+	project := r.Actor.Attributes[LabelProject]
+	if r.Type != "container" || project == "" || !isWatched(EventType(action)) {
+		return Event{}, false
+	}
+
+	event := Event{
+		Type:      EventType(action),
+		Project:   project,
+		Service:   r.Actor.Attributes[LabelService],
+		Container: r.Actor.ID,
+		Health:    health,
+		Time:      time.Unix(0, r.TimeNano),
+	}
+	if len(event.Container) > 12 {
+		event.Container = event.Container[:12]
+	}
+	if code, err := strconv.Atoi(r.Actor.Attributes["exitCode"]); err == nil {
+		event.ExitCode = code
+	}
+	return event, true
+}
+
+func isWatched(eventType EventType) bool {
+	for _, watched := range watchedEvents {
+		if watched == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// subscription est un abonné de l'EventHub
+type subscription struct {
+	project string
+	events  chan Event
+}
+
+// EventHub consomme le flux d'événements Docker et le diffuse aux abonnés.
+// Il utilise l'API Engine si disponible, sinon `docker events`.
+type EventHub struct {
+	Engine *EngineClient
+	// OnError reçoit l'erreur du flux une fois par coupure (optionnel)
+	OnError func(error)
+
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]*subscription
+}
+
+// eventRetryDelay est l'attente avant de rouvrir un flux interrompu ;
+// elle double à chaque échec consécutif, jusqu'à eventMaxRetryDelay
+var (
+	eventRetryDelay    = 2 * time.Second
+	eventMaxRetryDelay = time.Minute
+)
+
+// NewEventHub crée un hub d'événements (engine peut être nil)
+func NewEventHub(engine *EngineClient) *EventHub {
+	return &EventHub{
+		Engine: engine,
+		subs:   make(map[int]*subscription),
+	}
+}
+
+// Subscribe s'abonne aux événements d'un projet ("" pour tous les projets).
+// La fonction retournée termine l'abonnement et ferme le canal.
+func (h *EventHub) Subscribe(projectName string) (<-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextID
+	h.nextID++
+	sub := &subscription{project: projectName, events: make(chan Event, 64)}
+	h.subs[id] = sub
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subs, id)
+			close(sub.events)
+		})
+	}
+}
+
+// publish envoie l'événement aux abonnés concernés, sans bloquer sur un abonné lent
+func (h *EventHub) publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		if sub.project != "" && sub.project != event.Project {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// Run consomme le flux jusqu'à l'annulation du contexte, en le rouvrant
+// s'il est coupé (redémarrage du daemon par exemple). Chaque coupure est
+// signalée une fois à OnError et les tentatives s'espacent tant qu'elle dure.
+// Sans API Engine ni CLI docker, le flux ne peut pas s'ouvrir : Run abandonne
+// et retourne l'erreur.
+func (h *EventHub) Run(ctx context.Context) error {
+	delay := eventRetryDelay
+	failing := false
+	for {
+		err := h.consume(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		switch {
+		case err == nil:
+			// Flux terminé normalement : la coupure éventuelle est résolue
+			delay, failing = eventRetryDelay, false
+		case h.Engine == nil && errors.Is(err, exec.ErrNotFound):
+			return fmt.Errorf("flux d'événements indisponible: %w", err)
+		default:
+			if !failing && h.OnError != nil {
+				h.OnError(err)
+			}
+			if failing {
+				delay = min(2*delay, eventMaxRetryDelay)
+			}
+			failing = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// consume lit un flux d'événements jusqu'à sa fin
+func (h *EventHub) consume(ctx context.Context) error {
+	stream, err := h.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var raw rawEvent
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				// docker events qui s'arrête seul a échoué (daemon injoignable)
+				if cmdStream, ok := stream.(*commandStream); ok {
+					return cmdStream.wait()
+				}
+				return nil
+			}
+			return err
+		}
+		if event, ok := raw.toEvent(); ok {
+			h.publish(event)
+		}
+	}
+}
+
+// open ouvre le flux via l'API Engine, ou via le CLI docker si l'API est
+// absente ou ne répond pas (l'API est retentée à la réouverture suivante)
+func (h *EventHub) open(ctx context.Context) (io.ReadCloser, error) {
+	var engineErr error
+	if h.Engine != nil {
+		stream, err := h.Engine.Events(ctx, map[string][]string{
+			"type":  {"container"},
+			"label": {LabelProject},
+		})
+		if err == nil {
+			return stream, nil
+		}
+		engineErr = err
+	}
+
+	var stderr strings.Builder
+	cmd := exec.CommandContext(ctx, "docker", "events",
+		"--format", "{{json .}}",
+		"--filter", "type=container",
+		"--filter", "label="+LabelProject,
+	)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Join(engineErr, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Join(engineErr, err)
+	}
+	return &commandStream{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
+}
+
+// commandStream attend la fin du processus à la fermeture du flux
+type commandStream struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *strings.Builder
+	waited bool
+	err    error
+}
+
+// wait attend la fin du processus et retourne son erreur (avec stderr)
+func (s *commandStream) wait() error {
+	if !s.waited {
+		s.waited = true
+		if err := s.cmd.Wait(); err != nil {
+			s.err = &CommandError{Err: err, Stderr: strings.TrimSpace(s.stderr.String())}
+		}
+	}
+	return s.err
+}
+
+// Close ferme la sortie et libère le processus
+func (s *commandStream) Close() error {
+	s.ReadCloser.Close()
+	if !s.waited && s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	return s.wait()
+}
@@ -0,0 +1,165 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// withoutDockerCLI retire docker du PATH pour que le repli sur le CLI échoue
+func withoutDockerCLI(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+}
+
+// withRetryDelays raccourcit l'attente entre deux ouvertures du flux
+func withRetryDelays(t *testing.T, delay, max time.Duration) {
+	previous, previousMax := eventRetryDelay, eventMaxRetryDelay
+	eventRetryDelay, eventMaxRetryDelay = delay, max
+	t.Cleanup(func() { eventRetryDelay, eventMaxRetryDelay = previous, previousMax })
+}
+
+func TestRawEventToEvent(t *testing.T) {
+	raw := func(kind, action string, attrs map[string]string) rawEvent {
+		var r rawEvent
+		r.Type = kind
+		r.Action = action
+		r.Actor.ID = "0123456789abcdef"
+		r.Actor.Attributes = attrs
+		return r
+	}
+	compose := map[string]string{LabelProject: "web", LabelService: "app"}
+
+	tests := []struct {
+		name string
+		raw  rawEvent
+		want string // "" : événement ignoré
+	}{
+		{"start", raw("container", "start", compose), "web/app start"},
+		{"die", raw("container", "die", map[string]string{LabelProject: "web", LabelService: "app", "exitCode": "1"}), "web/app die (1)"},
+		{"santé", raw("container", "health_status: healthy", compose), "web/app health_status: healthy"},
+		{"exec ignoré", raw("container", "exec_start: sh", compose), ""},
+		{"hors compose", raw("container", "start", map[string]string{}), ""},
+		{"pas un container", raw("network", "connect", compose), ""},
+	}
+	for _, tt := range tests {
+		event, ok := tt.raw.toEvent()
+		got := ""
+		if ok {
+			got = event.String()
+			if event.Container != "0123456789ab" {
+				t.Errorf("%s : container %q, attendu l'ID court", tt.name, event.Container)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s : %q, attendu %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEventsStream(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `{"Type":"container","Action":"die","Actor":{"ID":"0123456789abcdef","Attributes":{"com.docker.compose.project":"web","com.docker.compose.service":"worker","exitCode":"137"}},"timeNano":1700000000000000000}`)
+		fmt.Fprintln(w, `{"Type":"container","Action":"exec_start: sh","Actor":{"ID":"1","Attributes":{"com.docker.compose.project":"web"}}}`)
+		fmt.Fprintln(w, `{"Type":"network","Action":"connect","Actor":{"ID":"2","Attributes":{}}}`)
+		fmt.Fprintln(w, `{"Type":"container","Action":"health_status: unhealthy","Actor":{"ID":"3","Attributes":{"com.docker.compose.project":"web","com.docker.compose.service":"app"}}}`)
+	}))
+
+	stream, err := engine.Events(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var events []Event
+	decoder := json.NewDecoder(stream)
+	for decoder.More() {
+		var raw rawEvent
+		if err := decoder.Decode(&raw); err != nil {
+			t.Fatal(err)
+		}
+		if event, ok := raw.toEvent(); ok {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("événements = %v, attendu 2", events)
+	}
+	if got := events[0]; got.Type != EventDie || got.Container != "0123456789ab" || got.ExitCode != 137 || got.String() != "web/worker die (137)" {
+		t.Errorf("die = %+v", got)
+	}
+	if got := events[1]; got.Type != EventHealth || got.Health != "unhealthy" {
+		t.Errorf("health = %+v", got)
+	}
+}
+
+func TestEventHubRetriesEngine(t *testing.T) {
+	withoutDockerCLI(t)
+	withRetryDelays(t, 10*time.Millisecond, 40*time.Millisecond)
+
+	var calls, failures int32
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 3 {
+			// Trois essais : le daemon ne répond pas correctement
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, `{"Type":"container","Action":"start","Actor":{"ID":"1","Attributes":{"com.docker.compose.project":"web","com.docker.compose.service":"app"}}}`)
+	}))
+
+	hub := NewEventHub(engine)
+	hub.OnError = func(err error) { atomic.AddInt32(&failures, 1) }
+	events, unsubscribe := hub.Subscribe("web")
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(stopped)
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	select {
+	case event := <-events:
+		if event.Type != EventStart || event.Service != "app" {
+			t.Errorf("événement = %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("l'API Engine n'a pas été retentée après l'échec")
+	}
+	if got := atomic.LoadInt32(&failures); got != 1 {
+		t.Errorf("OnError appelé %d fois, attendu 1 pour une même coupure", got)
+	}
+	if hub.Engine != engine {
+		t.Error("Run a modifié hub.Engine")
+	}
+}
+
+func TestEventHubGivesUpWithoutDocker(t *testing.T) {
+	withoutDockerCLI(t)
+
+	done := make(chan error, 1)
+	go func() { done <- NewEventHub(nil).Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "flux d'événements indisponible") {
+			t.Errorf("erreur = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run réessaie indéfiniment sans API Engine ni CLI docker")
+	}
+}
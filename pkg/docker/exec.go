@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// DefaultShells sont essayés dans l'ordre par Shell
+var DefaultShells = []string{"bash", "sh", "ash"}
+
+// ExecOptions contrôle Exec et Shell
+type ExecOptions struct {
+	TTY     bool   // alloue un pseudo-terminal (sinon exec -T)
+	User    string // utilisateur dans le container
+	Workdir string // répertoire de travail dans le container
+}
+
+// Exec exécute une commande dans le container d'un service en cours d'exécution.
+// Les flux du Manager sont attachés à la commande ; aucun timeout n'est appliqué.
+func (m *Manager) Exec(ctx context.Context, p *project.Project, service string, command []string, opts ExecOptions) error {
+	if len(command) == 0 {
+		return fmt.Errorf("aucune commande à exécuter")
+	}
+
+	args := []string{"exec"}
+	if !opts.TTY {
+		args = append(args, "-T")
+	}
+	if opts.User != "" {
+		args = append(args, "--user", opts.User)
+	}
+	if opts.Workdir != "" {
+		args = append(args, "--workdir", opts.Workdir)
+	}
+	args = append(args, service)
+	args = append(args, command...)
+
+	inv := m.invocation(p, args...)
+	inv.Stdin = m.Stdin
+	inv.Stdout = m.Stdout
+	inv.Stderr = m.Stderr
+	return stepErr(ctx, "exec", m.Runner.Run(ctx, inv))
+}
+
+// Shell ouvre un shell interactif dans un service. Si shell est vide,
+// le premier shell disponible parmi DefaultShells est utilisé.
+func (m *Manager) Shell(ctx context.Context, p *project.Project, service string, shell string, opts ExecOptions) error {
+	if shell == "" {
+		detected, err := m.DetectShell(ctx, p, service)
+		if err != nil {
+			return err
+		}
+		shell = detected
+	}
+
+	return m.Exec(ctx, p, service, []string{shell}, opts)
+}
+
+// DetectShell retourne le premier shell de DefaultShells présent dans le container
+func (m *Manager) DetectShell(ctx context.Context, p *project.Project, service string) (string, error) {
+	for _, shell := range DefaultShells {
+		probeCtx, cancel := withTimeout(ctx, m.Timeouts.Query)
+		inv := m.invocation(p, "exec", "-T", service, shell, "-c", "exit 0")
+		inv.Stdout = io.Discard
+		inv.Stderr = io.Discard
+		err := m.Runner.Run(probeCtx, inv)
+		cancel()
+
+		if ctx.Err() != nil {
+			return "", &StepError{Step: "exec", Err: ctx.Err()}
+		}
+		if err == nil {
+			return shell, nil
+		}
+	}
+	return "", fmt.Errorf("aucun shell trouvé dans le service %s (essayé: %v) ; le service est-il démarré ?", service, DefaultShells)
+}
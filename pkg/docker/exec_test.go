@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExecArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ExecOptions
+		command []string
+		want    []string
+	}{
+		{"sans tty", ExecOptions{}, []string{"ls", "-la"}, []string{"exec", "-T", "app", "ls", "-la"}},
+		{"tty", ExecOptions{TTY: true}, []string{"bash"}, []string{"exec", "app", "bash"}},
+		{
+			"user et workdir",
+			ExecOptions{User: "www-data", Workdir: "/var/www"},
+			[]string{"php", "artisan"},
+			[]string{"exec", "-T", "--user", "www-data", "--workdir", "/var/www", "app", "php", "artisan"},
+		},
+	}
+
+	for _, tt := range tests {
+		fake := &fakeRunner{}
+		mgr := &Manager{Runner: fake.runner()}
+		if err := mgr.Exec(context.Background(), testProject(), "app", tt.command, tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		if got := fake.commands(t, testBase); !reflect.DeepEqual(got, [][]string{tt.want}) {
+			t.Errorf("%s : commandes = %q, attendu %q", tt.name, got, tt.want)
+		}
+	}
+
+	if err := (&Manager{}).Exec(context.Background(), testProject(), "app", nil, ExecOptions{}); err == nil {
+		t.Error("commande vide : erreur attendue")
+	}
+}
+
+func TestDetectShell(t *testing.T) {
+	// bash absent du container : sh est retenu
+	var tried []string
+	runner := RunnerFunc(func(ctx context.Context, inv Invocation) error {
+		shell := inv.Args[len(inv.Args)-3]
+		tried = append(tried, shell)
+		if shell == "bash" {
+			return errors.New("exit status 126")
+		}
+		return nil
+	})
+	mgr := &Manager{Runner: runner}
+
+	shell, err := mgr.DetectShell(context.Background(), testProject(), "app")
+	if err != nil || shell != "sh" {
+		t.Errorf("DetectShell = %q, %v, attendu sh", shell, err)
+	}
+	if !reflect.DeepEqual(tried, []string{"bash", "sh"}) {
+		t.Errorf("shells essayés = %q", tried)
+	}
+
+	failing := &Manager{Runner: RunnerFunc(func(ctx context.Context, inv Invocation) error {
+		return errors.New("service \"app\" is not running")
+	})}
+	if _, err := failing.DetectShell(context.Background(), testProject(), "app"); err == nil {
+		t.Error("aucun shell : erreur attendue")
+	}
+}
@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// ProgressEvent décrit l'avancement d'une opération du Manager
+type ProgressEvent struct {
+	Project string
+	Step    string // build, up, down, restart...
+	Done    bool   // false au début de l'étape, true quand l'opération a réussi
+	Message string // message prêt à afficher (ex: "🚀 Démarrage du projet web...")
+}
+
+// ProgressFunc reçoit les événements de progression
+type ProgressFunc func(ProgressEvent)
+
+// report publie un événement de progression.
+// Sans callback, le message est écrit sur la sortie standard du Manager.
+func (m *Manager) report(p *project.Project, step string, done bool, format string, args ...interface{}) {
+	event := ProgressEvent{
+		Project: p.Name,
+		Step:    step,
+		Done:    done,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	if m.Progress != nil {
+		m.Progress(event)
+		return
+	}
+	if m.Stdout != nil {
+		fmt.Fprintln(m.Stdout, event.Message)
+	}
+}
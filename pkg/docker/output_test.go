@@ -0,0 +1,57 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+func TestReportWritesToStdout(t *testing.T) {
+	var stdout bytes.Buffer
+	mgr := &Manager{Stdout: &stdout}
+
+	mgr.report(&project.Project{Name: "web"}, "up", false, "🚀 Démarrage du projet %s...", "web")
+	if got := stdout.String(); got != "🚀 Démarrage du projet web...\n" {
+		t.Errorf("sortie = %q", got)
+	}
+
+	// Sans sortie ni callback, report ne fait rien
+	(&Manager{}).report(&project.Project{Name: "web"}, "up", false, "ignoré")
+}
+
+func TestReportCallsProgress(t *testing.T) {
+	var stdout bytes.Buffer
+	var events []ProgressEvent
+	mgr := &Manager{Stdout: &stdout, Progress: func(e ProgressEvent) { events = append(events, e) }}
+
+	mgr.report(&project.Project{Name: "web"}, "down", true, "✅ %d containers arrêtés", 2)
+
+	want := []ProgressEvent{{Project: "web", Step: "down", Done: true, Message: "✅ 2 containers arrêtés"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("événements = %+v, attendu %+v", events, want)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("sortie = %q, attendu rien (le callback remplace l'affichage)", stdout.String())
+	}
+}
+
+func TestComposeUsesManagerStreams(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	runner := RunnerFunc(func(ctx context.Context, inv Invocation) error {
+		fmt.Fprint(inv.Stdout, "out")
+		fmt.Fprint(inv.Stderr, "err")
+		return nil
+	})
+	mgr := &Manager{Runner: runner, Stdout: &stdout, Stderr: &stderr}
+
+	if err := mgr.compose(context.Background(), &project.Project{Name: "web"}, "stop"); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "out" || stderr.String() != "err" {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+}
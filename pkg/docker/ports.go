@@ -0,0 +1,346 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// PublishedPort est un port qu'un service publiera sur l'hôte
+type PublishedPort struct {
+	Service  string
+	HostIP   string
+	HostPort int
+	Proto    string
+}
+
+// String retourne le port au format "8080/tcp" (ou "127.0.0.1:8080/tcp")
+func (p PublishedPort) String() string {
+	if isWildcardIP(p.HostIP) {
+		return fmt.Sprintf("%d/%s", p.HostPort, p.Proto)
+	}
+	return fmt.Sprintf("%s/%s", net.JoinHostPort(p.HostIP, strconv.Itoa(p.HostPort)), p.Proto)
+}
+
+// composePort est une entrée ports: de la config compose (syntaxe courte ou longue)
+type composePort struct {
+	HostIP    string `yaml:"host_ip"`
+	Published string `yaml:"published"`
+	Protocol  string `yaml:"protocol"`
+}
+
+// UnmarshalYAML décode "127.0.0.1:8080:80/udp" ou {published: 8080, target: 80}
+func (c *composePort) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = parseShortPort(value.Value)
+		return nil
+	}
+
+	type longPort composePort
+	var port longPort
+	if err := value.Decode(&port); err != nil {
+		return err
+	}
+	*c = composePort(port)
+	return nil
+}
+
+// parseShortPort lit la syntaxe courte [HOST_IP:][HOST_PORT:]CONTAINER_PORT[/PROTO]
+func parseShortPort(spec string) composePort {
+	var port composePort
+	if slash := strings.LastIndex(spec, "/"); slash >= 0 {
+		port.Protocol = spec[slash+1:]
+		spec = spec[:slash]
+	}
+
+	// IPv6 entre crochets : [::1]:8080:80
+	if strings.HasPrefix(spec, "[") {
+		if end := strings.Index(spec, "]:"); end >= 0 {
+			port.HostIP = spec[1:end]
+			spec = spec[end+2:]
+		}
+	}
+
+	parts := strings.Split(spec, ":")
+	switch {
+	case len(parts) == 2:
+		port.Published = parts[0]
+	case len(parts) >= 3:
+		port.HostIP = strings.Join(parts[:len(parts)-2], ":")
+		port.Published = parts[len(parts)-2]
+	}
+	return port
+}
+
+// hostPorts développe le port publié ("8080" ou la plage "8000-8002")
+func (c composePort) hostPorts() []int {
+	published := strings.TrimSpace(c.Published)
+	if published == "" {
+		return nil
+	}
+
+	first, last, isRange := strings.Cut(published, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return nil
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return nil
+		}
+	}
+
+	ports := make([]int, 0, end-start+1)
+	for port := start; port <= end; port++ {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// parsePublishedPorts extrait les ports publiés d'une sortie de compose config
+func parsePublishedPorts(data []byte) ([]PublishedPort, error) {
+	var resolved struct {
+		Services map[string]struct {
+			Ports []composePort `yaml:"ports"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &resolved); err != nil {
+		return nil, fmt.Errorf("config compose invalide: %w", err)
+	}
+
+	var ports []PublishedPort
+	for service, definition := range resolved.Services {
+		for _, port := range definition.Ports {
+			proto := strings.ToLower(port.Protocol)
+			if proto == "" {
+				proto = "tcp"
+			}
+			for _, hostPort := range port.hostPorts() {
+				ports = append(ports, PublishedPort{
+					Service:  service,
+					HostIP:   port.HostIP,
+					HostPort: hostPort,
+					Proto:    proto,
+				})
+			}
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].HostPort != ports[j].HostPort {
+			return ports[i].HostPort < ports[j].HostPort
+		}
+		return ports[i].Service < ports[j].Service
+	})
+	return ports, nil
+}
+
+// GetPublishedPorts retourne les ports que le projet publiera sur l'hôte,
+// d'après la config compose résolue (fichiers, profils et variables appliqués)
+func (m *Manager) GetPublishedPorts(ctx context.Context, p *project.Project) ([]PublishedPort, error) {
+	output, err := m.composeOutput(ctx, p, "config")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture de la config compose: %w", err)
+	}
+	return parsePublishedPorts(output)
+}
+
+// PortConflict associe un port demandé à ce qui l'occupe déjà
+type PortConflict struct {
+	Port   PublishedPort
+	Holder string
+}
+
+// PortConflictError est retournée par StartProject quand des ports sont déjà pris
+type PortConflictError struct {
+	Project   string
+	Conflicts []PortConflict
+}
+
+// Error liste chaque port occupé et son détenteur
+func (e *PortConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ports déjà utilisés, démarrage de %s annulé :", e.Project)
+	for _, conflict := range e.Conflicts {
+		fmt.Fprintf(&b, "\n  - %s (service %s) : %s", conflict.Port, conflict.Port.Service, conflict.Holder)
+	}
+	b.WriteString("\n(--ignore-port-conflicts pour démarrer quand même)")
+	return b.String()
+}
+
+// portHolder est un port publié par un container déjà en cours d'exécution
+type portHolder struct {
+	binding   portBinding
+	project   string
+	service   string
+	container string
+}
+
+// describe indique qui détient le port
+func (h portHolder) describe() string {
+	if h.project != "" {
+		return fmt.Sprintf("projet %s (service %s)", h.project, h.service)
+	}
+	return fmt.Sprintf("container %s", h.container)
+}
+
+// runningPortHolders liste les ports publiés par les containers en cours
+func (m *Manager) runningPortHolders(ctx context.Context) ([]portHolder, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	var holders []portHolder
+	if m.Engine != nil {
+		if containers, err := m.Engine.ListContainers(ctx, false, nil); err == nil {
+			for _, container := range containers {
+				name := ""
+				if len(container.Names) > 0 {
+					name = strings.TrimPrefix(container.Names[0], "/")
+				}
+				for _, binding := range engineBindings(container.Ports) {
+					holders = append(holders, portHolder{
+						binding:   binding,
+						project:   container.Project(),
+						service:   container.Service(),
+						container: name,
+					})
+				}
+			}
+			return holders, nil
+		}
+	}
+
+	output, err := dockerOutput(ctx, "ps", "--format",
+		fmt.Sprintf("{{.Names}}\t{{.Label \"%s\"}}\t{{.Label \"%s\"}}\t{{.Ports}}", LabelProject, LabelService))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des containers: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		for _, binding := range parsePorts(fields[3]) {
+			holders = append(holders, portHolder{
+				binding:   binding,
+				project:   fields[1],
+				service:   fields[2],
+				container: fields[0],
+			})
+		}
+	}
+	return holders, nil
+}
+
+// CheckPortConflicts vérifie que les ports publiés par le projet (ou par les
+// services indiqués) sont libres : ni publiés par un autre container, ni en
+// écoute sur l'hôte. Les ports déjà publiés par le projet lui-même sont ignorés.
+func (m *Manager) CheckPortConflicts(ctx context.Context, p *project.Project, services []string) ([]PortConflict, error) {
+	ports, err := m.GetPublishedPorts(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	ports = filterPortsByService(ports, services)
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	holders, err := m.runningPortHolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []PortConflict
+	for _, port := range ports {
+		holder, own := findHolder(holders, port, p.Name)
+		switch {
+		case own:
+			continue
+		case holder != "":
+			conflicts = append(conflicts, PortConflict{Port: port, Holder: holder})
+		case portInUse(port):
+			conflicts = append(conflicts, PortConflict{Port: port, Holder: "un autre processus de l'hôte (port déjà en écoute)"})
+		}
+	}
+	return conflicts, nil
+}
+
+// filterPortsByService garde les ports des services demandés (tous si vide)
+func filterPortsByService(ports []PublishedPort, services []string) []PublishedPort {
+	if len(services) == 0 {
+		return ports
+	}
+	wanted := make(map[string]bool, len(services))
+	for _, service := range services {
+		wanted[service] = true
+	}
+
+	var filtered []PublishedPort
+	for _, port := range ports {
+		if wanted[port.Service] {
+			filtered = append(filtered, port)
+		}
+	}
+	return filtered
+}
+
+// findHolder cherche un container qui publie déjà ce port. own indique que
+// le port est publié par le projet lui-même (il sera réutilisé par up).
+func findHolder(holders []portHolder, port PublishedPort, projectName string) (holder string, own bool) {
+	for _, h := range holders {
+		proto := h.binding.Proto
+		if proto == "" {
+			proto = "tcp"
+		}
+		if h.binding.HostPort != strconv.Itoa(port.HostPort) || proto != port.Proto {
+			continue
+		}
+		if !isWildcardIP(h.binding.HostIP) && !isWildcardIP(port.HostIP) && h.binding.HostIP != port.HostIP {
+			continue
+		}
+		if h.project == projectName {
+			return "", true
+		}
+		holder = h.describe()
+	}
+	return holder, false
+}
+
+// portInUse tente d'ouvrir le port sur l'hôte ; seule une adresse déjà
+// utilisée compte comme conflit (pas un port privilégié refusé par exemple)
+func portInUse(port PublishedPort) bool {
+	host := port.HostIP
+	if isWildcardIP(host) {
+		host = ""
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port.HostPort))
+
+	var err error
+	if port.Proto == "udp" {
+		var conn net.PacketConn
+		if conn, err = net.ListenPacket("udp", address); err == nil {
+			conn.Close()
+		}
+	} else {
+		var listener net.Listener
+		if listener, err = net.Listen("tcp", address); err == nil {
+			listener.Close()
+		}
+	}
+	return errors.Is(err, syscall.EADDRINUSE)
+}
+
+// isWildcardIP indique une adresse d'écoute sur toutes les interfaces
+func isWildcardIP(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
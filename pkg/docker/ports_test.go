@@ -0,0 +1,146 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParsePublishedPorts(t *testing.T) {
+	config := `
+services:
+  web:
+    ports:
+      - "8080:80"
+      - "127.0.0.1:8443:443"
+      - "[::1]:9000:9000"
+      - "53:53/udp"
+      - "3000"
+  api:
+    ports:
+      - target: 80
+        published: "8000-8001"
+        host_ip: 0.0.0.0
+        protocol: tcp
+`
+	ports, err := parsePublishedPorts([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, port := range ports {
+		got = append(got, port.Service+" "+port.String())
+	}
+	want := []string{
+		"web 53/udp",
+		"api 8000/tcp",
+		"api 8001/tcp",
+		"web 8080/tcp",
+		"web 127.0.0.1:8443/tcp",
+		"web [::1]:9000/tcp",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ports = %q, attendu %q", got, want)
+	}
+
+	if _, err := parsePublishedPorts([]byte("services: [")); err == nil {
+		t.Error("config invalide : erreur attendue")
+	}
+}
+
+func TestFindHolder(t *testing.T) {
+	holders := []portHolder{
+		{binding: portBinding{HostIP: "0.0.0.0", HostPort: "8080", Proto: "tcp"}, project: "blog", service: "nginx"},
+		{binding: portBinding{HostIP: "127.0.0.1", HostPort: "5432", Proto: "tcp"}, container: "postgres"},
+		{binding: portBinding{HostIP: "0.0.0.0", HostPort: "3000", Proto: "tcp"}, project: "web", service: "app"},
+	}
+
+	tests := []struct {
+		name       string
+		port       PublishedPort
+		wantHolder string
+		wantOwn    bool
+	}{
+		{"autre projet", PublishedPort{HostPort: 8080, Proto: "tcp"}, "projet blog (service nginx)", false},
+		{"autre protocole", PublishedPort{HostPort: 8080, Proto: "udp"}, "", false},
+		{"container hors compose", PublishedPort{HostIP: "127.0.0.1", HostPort: 5432, Proto: "tcp"}, "container postgres", false},
+		{"autre adresse", PublishedPort{HostIP: "192.168.1.10", HostPort: 5432, Proto: "tcp"}, "", false},
+		{"projet lui-même", PublishedPort{HostPort: 3000, Proto: "tcp"}, "", true},
+	}
+	for _, tt := range tests {
+		holder, own := findHolder(holders, tt.port, "web")
+		if holder != tt.wantHolder || own != tt.wantOwn {
+			t.Errorf("%s : %q, %v, attendu %q, %v", tt.name, holder, own, tt.wantHolder, tt.wantOwn)
+		}
+	}
+}
+
+func TestPortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("écoute locale impossible: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if !portInUse(PublishedPort{HostIP: "127.0.0.1", HostPort: port, Proto: "tcp"}) {
+		t.Errorf("port %d en écoute non détecté", port)
+	}
+	listener.Close()
+	if portInUse(PublishedPort{HostIP: "127.0.0.1", HostPort: port, Proto: "tcp"}) {
+		t.Errorf("port %d libéré toujours considéré utilisé", port)
+	}
+}
+
+func TestStartRefusesPortConflicts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("écoute locale impossible: %v", err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	fake := &fakeRunner{output: func(args []string) string {
+		if args[len(args)-1] != "config" {
+			return ""
+		}
+		return "services:\n" +
+			"  app:\n    ports: [\"127.0.0.1:" + strconv.Itoa(busy) + ":80\"]\n" +
+			"  db:\n    ports: [\"127.0.0.1:15432:5432\"]\n"
+	}}
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Container{{
+			ID:     "1",
+			Labels: map[string]string{LabelProject: "blog", LabelService: "postgres"},
+			Ports:  []Port{{IP: "127.0.0.1", PrivatePort: 5432, PublicPort: 15432, Type: "tcp"}},
+		}})
+	}))
+	mgr := &Manager{Runner: fake.runner(), Engine: engine}
+
+	err = mgr.StartProject(context.Background(), testProject(), StartOptions{})
+	var conflictErr *PortConflictError
+	if !errors.As(err, &conflictErr) || len(conflictErr.Conflicts) != 2 {
+		t.Fatalf("erreur = %v, attendu deux conflits", err)
+	}
+	if !strings.Contains(err.Error(), "projet blog (service postgres)") || !strings.Contains(err.Error(), "port déjà en écoute") {
+		t.Errorf("erreur = %q", err)
+	}
+	if got := fake.commands(t, testBase); len(got) != 0 {
+		t.Errorf("commandes = %q, attendu aucun build/up", got)
+	}
+
+	// --ignore-port-conflicts : démarrage malgré les conflits
+	fake.calls = nil
+	if err := mgr.StartProject(context.Background(), testProject(), StartOptions{NoBuild: true, IgnorePortConflicts: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.commands(t, testBase); !reflect.DeepEqual(got, [][]string{{"up", "-d", "--no-build"}}) {
+		t.Errorf("commandes = %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/health"
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// ServiceProbe est le résultat d'une sonde sur un service
+type ServiceProbe struct {
+	Service    string
+	Configured bool // health_check de projects.yml (sinon URL dérivée des ports)
+	health.Result
+}
+
+// ProbeServices sonde chaque service du projet : le health_check configuré
+// s'il existe, sinon chaque port publié (GetServiceEndpoints) : requête HTTP
+// pour une URL, connexion TCP pour les autres ports (UDP ignoré). Les sondes
+// sont lancées en parallèle (DefaultStatusWorkers au plus) et triées par
+// service puis cible.
+func (m *Manager) ProbeServices(ctx context.Context, p *project.Project, checks map[string]string) ([]ServiceProbe, error) {
+	endpoints, err := m.GetServiceEndpoints(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	type job struct {
+		service string
+		target  string
+		http    bool
+		check   *health.Check
+	}
+	var jobs []job
+	for service, value := range checks {
+		check, err := health.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job{service: service, target: check.String(), check: &check})
+	}
+	for service, list := range endpoints {
+		if _, configured := checks[service]; configured {
+			continue
+		}
+		for _, endpoint := range list {
+			switch {
+			case endpoint.URL != "":
+				jobs = append(jobs, job{service: service, target: endpoint.URL, http: true})
+			case endpoint.Proto == "tcp":
+				jobs = append(jobs, job{service: service, target: endpoint.Address()})
+			}
+		}
+	}
+
+	probes := make([]ServiceProbe, len(jobs))
+	slots := make(chan struct{}, DefaultStatusWorkers)
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func(i int, j job) {
+			defer wg.Done()
+			// Le délai de la sonde ne court qu'une fois la place obtenue
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			probeCtx, cancel := context.WithTimeout(ctx, health.DefaultTimeout)
+			defer cancel()
+
+			probe := ServiceProbe{Service: j.service, Configured: j.check != nil}
+			switch {
+			case j.http:
+				probe.Result = health.ProbeHTTP(probeCtx, j.target)
+			case j.check == nil:
+				probe.Result = health.ProbeTCP(probeCtx, j.target)
+			default:
+				probe.Result = m.probeCheck(probeCtx, p, j.service, *j.check)
+			}
+			probes[i] = probe
+		}(i, j)
+	}
+	wg.Wait()
+
+	sort.Slice(probes, func(i, j int) bool {
+		if probes[i].Service != probes[j].Service {
+			return probes[i].Service < probes[j].Service
+		}
+		return probes[i].Target < probes[j].Target
+	})
+	return probes, nil
+}
+
+// probeCheck exécute un health_check configuré et mesure sa durée
+func (m *Manager) probeCheck(ctx context.Context, p *project.Project, service string, check health.Check) health.Result {
+	switch check.Kind {
+	case health.KindHTTP:
+		return health.ProbeHTTP(ctx, check.Target)
+	case health.KindTCP:
+		return health.ProbeTCP(ctx, check.Target)
+	default:
+		start := time.Now()
+		err := m.runCheck(ctx, p, service, check)
+		return health.Result{
+			Kind:    check.Kind,
+			Target:  check.Target,
+			OK:      err == nil,
+			Latency: time.Since(start),
+			Err:     err,
+		}
+	}
+}
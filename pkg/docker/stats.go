@@ -0,0 +1,357 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ResourceUsage est la consommation de ressources d'un ou plusieurs containers
+type ResourceUsage struct {
+	Containers int
+	CPUPercent float64 // en % d'un cœur (peut dépasser 100)
+	MemUsage   uint64
+	MemLimit   uint64
+	NetRx      uint64
+	NetTx      uint64
+	BlockRead  uint64
+	BlockWrite uint64
+	PIDs       int
+}
+
+// Add cumule la consommation d'un autre container
+func (u *ResourceUsage) Add(other ResourceUsage) {
+	u.Containers += other.Containers
+	u.CPUPercent += other.CPUPercent
+	u.MemUsage += other.MemUsage
+	u.NetRx += other.NetRx
+	u.NetTx += other.NetTx
+	u.BlockRead += other.BlockRead
+	u.BlockWrite += other.BlockWrite
+	u.PIDs += other.PIDs
+	// La limite est celle de l'hôte (ou du container) : garder la plus grande
+	if other.MemLimit > u.MemLimit {
+		u.MemLimit = other.MemLimit
+	}
+}
+
+// ServiceStats est la consommation d'un service (toutes ses répliques)
+type ServiceStats struct {
+	Name string
+	ResourceUsage
+}
+
+// ProjectStats est la consommation d'un projet et de chacun de ses services
+type ProjectStats struct {
+	Name     string
+	Total    ResourceUsage
+	Services []ServiceStats
+}
+
+// statsLine est une ligne de docker stats --format '{{json .}}'
+type statsLine struct {
+	ID       string `json:"ID"`
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+	NetIO    string `json:"NetIO"`
+	BlockIO  string `json:"BlockIO"`
+	PIDs     string `json:"PIDs"`
+}
+
+// usage convertit les valeurs lisibles de docker stats
+func (l statsLine) usage() ResourceUsage {
+	usage := ResourceUsage{Containers: 1}
+	usage.CPUPercent, _ = strconv.ParseFloat(strings.TrimSuffix(l.CPUPerc, "%"), 64)
+	usage.MemUsage, usage.MemLimit = parseSizePair(l.MemUsage)
+	usage.NetRx, usage.NetTx = parseSizePair(l.NetIO)
+	usage.BlockRead, usage.BlockWrite = parseSizePair(l.BlockIO)
+	usage.PIDs, _ = strconv.Atoi(l.PIDs)
+	return usage
+}
+
+// parseSizePair lit "12MiB / 7.6GiB"
+func parseSizePair(value string) (uint64, uint64) {
+	first, second, _ := strings.Cut(value, "/")
+	return parseSize(first), parseSize(second)
+}
+
+// sizeUnits sont les unités utilisées par docker stats (décimales et binaires)
+var sizeUnits = map[string]float64{
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parseSize lit une taille lisible (ex: "1.5GiB", "300kB", "0B")
+func parseSize(value string) uint64 {
+	value = strings.TrimSpace(value)
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i <= 0 {
+		return 0
+	}
+
+	number, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil {
+		return 0
+	}
+	multiplier, ok := sizeUnits[strings.ToLower(strings.TrimSpace(value[i:]))]
+	if !ok {
+		return 0
+	}
+	return uint64(number * multiplier)
+}
+
+// FormatBytes affiche une taille en unités binaires (ex: "512.0 MiB")
+func FormatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// composeContainer identifie un container compose en cours d'exécution
+type composeContainer struct {
+	ID      string
+	Project string
+	Service string
+}
+
+// runningComposeContainers liste les containers des services compose en cours
+// d'exécution (d'un projet, ou de tous si projectName est vide). Les containers
+// one-off (compose run) sont exclus.
+func (m *Manager) runningComposeContainers(ctx context.Context, projectName string) ([]composeContainer, error) {
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	filter := LabelProject
+	if projectName != "" {
+		filter = fmt.Sprintf("%s=%s", LabelProject, projectName)
+	}
+	serviceFilter := fmt.Sprintf("%s=False", LabelOneOff)
+
+	var result []composeContainer
+	if m.Engine != nil {
+		if containers, err := m.Engine.ListContainers(ctx, false, map[string][]string{"label": {filter, serviceFilter}}); err == nil {
+			for _, container := range containers {
+				result = append(result, composeContainer{
+					ID:      shortID(container.ID),
+					Project: container.Project(),
+					Service: container.Service(),
+				})
+			}
+			return result, nil
+		}
+	}
+
+	output, err := dockerOutput(ctx, "ps", "--filter", "label="+filter, "--filter", "label="+serviceFilter, "--format",
+		fmt.Sprintf("{{.ID}}\t{{.Label \"%s\"}}\t{{.Label \"%s\"}}", LabelProject, LabelService))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des containers: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		result = append(result, composeContainer{ID: shortID(fields[0]), Project: fields[1], Service: fields[2]})
+	}
+	return result, nil
+}
+
+// shortID tronque un identifiant de container à 12 caractères
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// GetStats mesure la consommation (CPU, mémoire, réseau, disque) des containers
+// compose en cours, regroupée par projet puis par service. Si projectName est
+// vide, tous les projets sont inclus. Les projets sont triés par mémoire utilisée.
+// Un container arrêté ou supprimé entre le listing et la mesure est ignoré.
+func (m *Manager) GetStats(ctx context.Context, projectName string) ([]ProjectStats, error) {
+	containers, err := m.runningComposeContainers(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, nil
+	}
+
+	// Le daemon attend un second échantillon pour calculer le CPU
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	var usageByID map[string]ResourceUsage
+	if m.Engine != nil {
+		usageByID, err = m.engineStats(ctx, containers)
+	}
+	if m.Engine == nil || err != nil {
+		if usageByID, err = cliStats(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return aggregateStats(containers, usageByID), nil
+}
+
+// engineStats mesure chaque container via l'API Engine, en parallèle.
+// Les containers disparus (404) sont ignorés.
+func (m *Manager) engineStats(ctx context.Context, containers []composeContainer) (map[string]ResourceUsage, error) {
+	var mu sync.Mutex
+	usageByID := make(map[string]ResourceUsage)
+	var firstErr error
+
+	slots := make(chan struct{}, DefaultStatusWorkers)
+	var wg sync.WaitGroup
+	for _, container := range containers {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			stats, err := m.Engine.ContainerStats(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case IsNotFound(err):
+			case err != nil:
+				if firstErr == nil {
+					firstErr = err
+				}
+			default:
+				usageByID[id] = engineUsage(stats)
+			}
+		}(container.ID)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des statistiques: %w", firstErr)
+	}
+	return usageByID, nil
+}
+
+// engineUsage convertit un échantillon de l'API avec les mêmes calculs que docker stats
+func engineUsage(stats *ContainerStats) ResourceUsage {
+	usage := ResourceUsage{Containers: 1, PIDs: stats.PidsStats.Current}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	cpus := stats.CPUStats.OnlineCPUs
+	if cpus == 0 {
+		cpus = len(stats.CPUStats.CPUUsage.PercpuUsage)
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		usage.CPUPercent = cpuDelta / systemDelta * float64(cpus) * 100
+	}
+
+	// Le cache de pages n'est pas compté (total_inactive_file en cgroup v1, inactive_file en v2)
+	usage.MemUsage = stats.MemoryStats.Usage
+	cache, ok := stats.MemoryStats.Stats["total_inactive_file"]
+	if !ok {
+		cache = stats.MemoryStats.Stats["inactive_file"]
+	}
+	if cache < usage.MemUsage {
+		usage.MemUsage -= cache
+	}
+	usage.MemLimit = stats.MemoryStats.Limit
+
+	for _, network := range stats.Networks {
+		usage.NetRx += network.RxBytes
+		usage.NetTx += network.TxBytes
+	}
+	for _, entry := range stats.BlkioStats.IOServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			usage.BlockRead += entry.Value
+		case "write":
+			usage.BlockWrite += entry.Value
+		}
+	}
+	return usage
+}
+
+// cliStats mesure tous les containers en cours avec docker stats. Aucun ID
+// n'est passé : un container arrêté entre-temps ne fait pas échouer la commande.
+func cliStats(ctx context.Context) (map[string]ResourceUsage, error) {
+	output, err := dockerOutput(ctx, "stats", "--no-stream", "--format", "{{json .}}")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la lecture des statistiques: %w", err)
+	}
+
+	usageByID := make(map[string]ResourceUsage)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		var stats statsLine
+		if err := json.Unmarshal([]byte(line), &stats); err != nil {
+			continue
+		}
+		usageByID[shortID(stats.ID)] = stats.usage()
+	}
+	return usageByID, nil
+}
+
+// aggregateStats regroupe la consommation des containers par projet et service
+func aggregateStats(containers []composeContainer, usageByID map[string]ResourceUsage) []ProjectStats {
+	byProject := make(map[string]map[string]*ResourceUsage)
+	for _, container := range containers {
+		usage, ok := usageByID[container.ID]
+		if !ok {
+			continue
+		}
+		services := byProject[container.Project]
+		if services == nil {
+			services = make(map[string]*ResourceUsage)
+			byProject[container.Project] = services
+		}
+		if services[container.Service] == nil {
+			services[container.Service] = &ResourceUsage{}
+		}
+		services[container.Service].Add(usage)
+	}
+
+	stats := make([]ProjectStats, 0, len(byProject))
+	for name, services := range byProject {
+		projectStats := ProjectStats{Name: name}
+		for service, usage := range services {
+			projectStats.Total.Add(*usage)
+			projectStats.Services = append(projectStats.Services, ServiceStats{Name: service, ResourceUsage: *usage})
+		}
+		sort.Slice(projectStats.Services, func(i, j int) bool {
+			a, b := projectStats.Services[i], projectStats.Services[j]
+			if a.MemUsage != b.MemUsage {
+				return a.MemUsage > b.MemUsage
+			}
+			return a.Name < b.Name
+		})
+		stats = append(stats, projectStats)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total.MemUsage != stats[j].Total.MemUsage {
+			return stats[i].Total.MemUsage > stats[j].Total.MemUsage
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
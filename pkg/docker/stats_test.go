@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetStatsSkipsRemovedContainers(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/containers/json":
+			fmt.Fprint(w, `[
+				{"Id":"aaaaaaaaaaaa","Labels":{"com.docker.compose.project":"web","com.docker.compose.service":"app"}},
+				{"Id":"bbbbbbbbbbbb","Labels":{"com.docker.compose.project":"web","com.docker.compose.service":"worker"}}
+			]`)
+		case "/containers/aaaaaaaaaaaa/stats":
+			fmt.Fprint(w, `{
+				"cpu_stats":{"cpu_usage":{"total_usage":300},"system_cpu_usage":2000,"online_cpus":2},
+				"precpu_stats":{"cpu_usage":{"total_usage":100},"system_cpu_usage":1000},
+				"memory_stats":{"usage":1000,"limit":8000,"stats":{"inactive_file":200}},
+				"networks":{"eth0":{"rx_bytes":10,"tx_bytes":20}},
+				"blkio_stats":{"io_service_bytes_recursive":[{"op":"read","value":5},{"op":"write","value":7}]},
+				"pids_stats":{"current":3}
+			}`)
+		default:
+			// worker s'est arrêté entre le listing et la mesure
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"No such container: bbbbbbbbbbbb"}`)
+		}
+	}))
+	mgr := &Manager{Engine: engine}
+
+	stats, err := mgr.GetStats(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || len(stats[0].Services) != 1 {
+		t.Fatalf("stats = %+v, attendu le seul service app", stats)
+	}
+
+	got := stats[0].Services[0]
+	want := ResourceUsage{Containers: 1, CPUPercent: 40, MemUsage: 800, MemLimit: 8000,
+		NetRx: 10, NetTx: 20, BlockRead: 5, BlockWrite: 7, PIDs: 3}
+	if got.Name != "app" || got.ResourceUsage != want {
+		t.Errorf("app = %+v, attendu %+v", got, want)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  uint64
+	}{
+		{"0B", 0},
+		{"512B", 512},
+		{"300kB", 300000},
+		{"1.5GiB", 3 << 29},
+		{" 12MiB ", 12 << 20},
+		{"2.5MB", 2500000},
+		{"--", 0},
+		{"12XB", 0},
+	}
+	for _, tt := range tests {
+		if got := parseSize(tt.value); got != tt.want {
+			t.Errorf("parseSize(%q) = %d, attendu %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestStatsLineUsage(t *testing.T) {
+	line := statsLine{CPUPerc: "12.5%", MemUsage: "1MiB / 1GiB", NetIO: "1kB / 2kB", BlockIO: "0B / 4kB", PIDs: "7"}
+	want := ResourceUsage{Containers: 1, CPUPercent: 12.5, MemUsage: 1 << 20, MemLimit: 1 << 30,
+		NetRx: 1000, NetTx: 2000, BlockWrite: 4000, PIDs: 7}
+	if got := line.usage(); got != want {
+		t.Errorf("usage = %+v, attendu %+v", got, want)
+	}
+}
+
+func TestRunningContainersSkipsOneOff(t *testing.T) {
+	var gotFilters string
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFilters = r.URL.Query().Get("filters")
+		fmt.Fprint(w, `[{"Id":"aaaaaaaaaaaaaaaa","Labels":{"com.docker.compose.project":"web","com.docker.compose.service":"app"}}]`)
+	}))
+	mgr := &Manager{Engine: engine}
+
+	containers, err := mgr.runningComposeContainers(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var filters map[string][]string
+	if err := json.Unmarshal([]byte(gotFilters), &filters); err != nil {
+		t.Fatalf("filters invalide %q: %v", gotFilters, err)
+	}
+	want := []string{LabelProject + "=web", LabelOneOff + "=False"}
+	if !reflect.DeepEqual(filters["label"], want) {
+		t.Errorf("filtre label = %q, attendu %q", filters["label"], want)
+	}
+	if len(containers) != 1 || containers[0].ID != "aaaaaaaaaaaa" {
+		t.Errorf("containers = %+v", containers)
+	}
+}
+
+func TestAggregateStatsOrder(t *testing.T) {
+	containers := []composeContainer{
+		{ID: "1", Project: "web", Service: "worker"},
+		{ID: "2", Project: "web", Service: "app"},
+		{ID: "3", Project: "web", Service: "app"},
+		{ID: "4", Project: "web", Service: "cron"},
+		{ID: "5", Project: "blog", Service: "db"},
+		{ID: "6", Project: "shop", Service: "db"},
+	}
+	usage := map[string]ResourceUsage{
+		"1": {Containers: 1, MemUsage: 100},
+		"2": {Containers: 1, MemUsage: 50},
+		"3": {Containers: 1, MemUsage: 50},
+		"4": {Containers: 1, MemUsage: 100},
+		"5": {Containers: 1, MemUsage: 10},
+		"6": {Containers: 1, MemUsage: 10},
+	}
+
+	// Ordre stable malgré l'itération aléatoire des maps
+	for run := 0; run < 20; run++ {
+		stats := aggregateStats(containers, usage)
+
+		var projects, services []string
+		for _, p := range stats {
+			projects = append(projects, p.Name)
+		}
+		for _, s := range stats[0].Services {
+			services = append(services, fmt.Sprintf("%s:%d", s.Name, s.Containers))
+		}
+		if want := []string{"web", "blog", "shop"}; !reflect.DeepEqual(projects, want) {
+			t.Fatalf("projets = %q, attendu %q", projects, want)
+		}
+		if want := []string{"app:2", "cron:1", "worker:1"}; !reflect.DeepEqual(services, want) {
+			t.Fatalf("services = %q, attendu %q", services, want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// DefaultStatusWorkers est le nombre de projets interrogés simultanément par défaut
+const DefaultStatusWorkers = 8
+
+// eachProject appelle fn pour chaque projet avec un pool de StatusWorkers
+// goroutines. Chaque projet a son propre timeout (Timeouts.Query) : un projet
+// lent ne retarde pas les autres. fn modifie projects[i] sur place, l'ordre
+// des projets est donc conservé.
+func (m *Manager) eachProject(ctx context.Context, projects []project.Project, fn func(ctx context.Context, p *project.Project)) {
+	workers := m.StatusWorkers
+	if workers <= 0 {
+		workers = DefaultStatusWorkers
+	}
+	if workers > len(projects) {
+		workers = len(projects)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				projectCtx, cancel := withTimeout(ctx, m.Timeouts.Query)
+				fn(projectCtx, &projects[i])
+				cancel()
+			}
+		}()
+	}
+
+	for i := range projects {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// LoadServiceStatuses renseigne Services pour chaque projet démarré, en parallèle
+func (m *Manager) LoadServiceStatuses(ctx context.Context, projects []project.Project) {
+	m.eachProject(ctx, projects, func(ctx context.Context, p *project.Project) {
+		if !p.Running {
+			return
+		}
+		if services, err := m.GetServiceStatuses(ctx, p); err == nil {
+			p.Services = services
+		}
+	})
+}
+
+// GetServiceStatuses retourne l'état détaillé de chaque service du projet
+// (état, santé, code de sortie, uptime, container). Les services déclarés
+// dans le compose mais sans container apparaissent avec un Status vide.
+func (m *Manager) GetServiceStatuses(ctx context.Context, p *project.Project) ([]project.Service, error) {
+	infos, err := m.inspectProject(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]project.Service, 0, len(infos))
+	seen := make(map[string]struct{})
+	for _, info := range infos {
+		service := serviceFromInfo(info)
+		seen[service.Name] = struct{}{}
+		services = append(services, service)
+	}
+
+	// Ajouter les services déclarés qui n'ont pas de container
+	if declared, err := m.GetServices(ctx, p); err == nil {
+		for _, name := range declared {
+			if _, ok := seen[name]; !ok {
+				services = append(services, project.Service{Name: name})
+			}
+		}
+	}
+
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+// inspectProject retourne le détail des containers des services actifs du
+// projet (hors compose run et profils non sélectionnés), via l'API Engine si
+// possible, sinon via docker inspect
+func (m *Manager) inspectProject(ctx context.Context, p *project.Project) ([]ContainerInfo, error) {
+	if containers, err := m.engineContainers(ctx, p, true); err == nil {
+		ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+		defer cancel()
+
+		infos := make([]ContainerInfo, 0, len(containers))
+		for _, container := range containers {
+			info, err := m.Engine.InspectContainer(ctx, container.ID)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, *info)
+		}
+		return infos, nil
+	}
+
+	active := m.activeServices(ctx, p)
+
+	ctx, cancel := withTimeout(ctx, m.Timeouts.Query)
+	defer cancel()
+
+	ids, err := dockerOutput(ctx, "ps", "-aq",
+		"--filter", fmt.Sprintf("label=%s=%s", LabelProject, p.Name),
+		"--filter", fmt.Sprintf("label=%s=False", LabelOneOff))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des containers: %w", err)
+	}
+	if countLines(ids) == 0 {
+		return nil, nil
+	}
+
+	output, err := dockerOutput(ctx, append([]string{"inspect"}, strings.Fields(string(ids))...)...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'inspection des containers: %w", err)
+	}
+
+	var infos []ContainerInfo
+	if err := json.Unmarshal(output, &infos); err != nil {
+		return nil, fmt.Errorf("sortie de docker inspect invalide: %w", err)
+	}
+
+	if active == nil {
+		return infos, nil
+	}
+	var selected []ContainerInfo
+	for _, info := range infos {
+		if active[info.Config.Labels[LabelService]] {
+			selected = append(selected, info)
+		}
+	}
+	return selected, nil
+}
+
+// serviceFromInfo convertit le détail d'un container en project.Service
+func serviceFromInfo(info ContainerInfo) project.Service {
+	service := project.Service{
+		Name:      info.Config.Labels[LabelService],
+		Status:    info.State.Status,
+		ExitCode:  info.State.ExitCode,
+		Container: info.ID,
+		Ports:     formatBindings(info.NetworkSettings.Ports),
+	}
+	if len(service.Container) > 12 {
+		service.Container = service.Container[:12]
+	}
+	if info.State.Health != nil {
+		service.Health = info.State.Health.Status
+	}
+	if startedAt, err := time.Parse(time.RFC3339Nano, info.State.StartedAt); err == nil && startedAt.Year() > 1 {
+		service.StartedAt = startedAt
+	}
+	return service
+}
+
+// formatBindings reproduit la colonne Ports de docker ps
+func formatBindings(ports map[string][]PortBinding) string {
+	var parts []string
+	for containerPort, bindings := range ports {
+		for _, binding := range bindings {
+			if binding.HostPort == "" {
+				continue
+			}
+			host := binding.HostIP
+			if strings.Contains(host, ":") {
+				host = "[" + host + "]"
+			}
+			parts = append(parts, fmt.Sprintf("%s:%s->%s", host, binding.HostPort, containerPort))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// dockerOutput exécute le CLI docker et retourne sa sortie standard
+func dockerOutput(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	var stderr strings.Builder
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, &StepError{Step: "docker " + args[0], Err: ctx.Err()}
+		}
+		return nil, &CommandError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.Bytes(), nil
+}
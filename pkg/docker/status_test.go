@@ -0,0 +1,190 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+func TestServiceFromInfo(t *testing.T) {
+	var info ContainerInfo
+	err := json.Unmarshal([]byte(`{
+		"Id": "0123456789abcdef",
+		"State": {"Status": "running", "StartedAt": "2026-01-01T10:00:00.5Z", "Health": {"Status": "unhealthy"}},
+		"Config": {"Labels": {"com.docker.compose.service": "app"}},
+		"NetworkSettings": {"Ports": {"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}, {"HostIp": "::", "HostPort": "8080"}], "9000/tcp": null}}
+	}`), &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := serviceFromInfo(info)
+	want := project.Service{
+		Name:      "app",
+		Status:    "running",
+		Health:    "unhealthy",
+		Container: "0123456789ab",
+		StartedAt: time.Date(2026, 1, 1, 10, 0, 0, 5e8, time.UTC),
+		Ports:     "0.0.0.0:8080->80/tcp, [::]:8080->80/tcp",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("service = %+v\nattendu  %+v", got, want)
+	}
+}
+
+func TestServiceFromInfoNeverStarted(t *testing.T) {
+	var info ContainerInfo
+	info.State.Status = "created"
+	info.State.StartedAt = "0001-01-01T00:00:00Z"
+
+	if got := serviceFromInfo(info); !got.StartedAt.IsZero() || got.Health != "" {
+		t.Errorf("service = %+v, attendu sans démarrage ni santé", got)
+	}
+}
+
+func TestGetServiceStatusesListsDeclaredServices(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/containers/json" {
+			fmt.Fprint(w, `[{"Id":"bbbbbbbbbbbb","Labels":{"com.docker.compose.project":"web","com.docker.compose.service":"worker"}}]`)
+			return
+		}
+		fmt.Fprint(w, `{"Id":"bbbbbbbbbbbb","State":{"Status":"exited","ExitCode":137},
+			"Config":{"Labels":{"com.docker.compose.service":"worker"}}}`)
+	}))
+	// Le compose déclare aussi app, jamais créé
+	fake := &fakeRunner{output: func(args []string) string { return "app\nworker\n" }}
+	mgr := &Manager{Engine: engine, Runner: fake.runner()}
+
+	services, err := mgr.GetServiceStatuses(context.Background(), &project.Project{Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var states []string
+	for _, service := range services {
+		states = append(states, service.Name+" "+service.StateString())
+	}
+	if want := "app non créé, worker exited (137)"; strings.Join(states, ", ") != want {
+		t.Errorf("services = %q, attendu %q", strings.Join(states, ", "), want)
+	}
+}
+
+func TestStatusSkipsOneOffContainers(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Container{
+			{ID: "1", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "app", LabelOneOff: "False"}},
+			{ID: "2", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "app", LabelOneOff: "True"}},
+		})
+	}))
+	mgr := &Manager{Engine: engine}
+	p := testProject()
+	p.Profiles = nil
+
+	running, count, err := mgr.GetStatus(context.Background(), p)
+	if err != nil || !running || count != 1 {
+		t.Errorf("GetStatus = %v, %d, %v, attendu 1 container (compose run exclu)", running, count, err)
+	}
+
+	projects := []project.Project{*p}
+	mgr.LoadStatuses(context.Background(), projects)
+	if projects[0].ServiceCount != 1 {
+		t.Errorf("LoadStatuses: ServiceCount = %d, attendu 1", projects[0].ServiceCount)
+	}
+}
+
+func TestStatusFiltersInactiveProfiles(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Container{
+			{ID: "1", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "app"}},
+			{ID: "2", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "debugger"}},
+			{ID: "3", State: "running", Labels: map[string]string{LabelProject: "blog", LabelService: "debugger"}},
+		})
+	}))
+	// compose config --services avec --profile debug : debugger n'est pas actif
+	fake := &fakeRunner{output: func(args []string) string {
+		if reflect.DeepEqual(args[len(args)-2:], []string{"config", "--services"}) {
+			return "app\n"
+		}
+		return ""
+	}}
+	mgr := &Manager{Engine: engine, Runner: fake.runner()}
+
+	running, count, err := mgr.GetStatus(context.Background(), testProject())
+	if err != nil || !running || count != 1 {
+		t.Errorf("GetStatus = %v, %d, %v, attendu 1 container (profil inactif exclu)", running, count, err)
+	}
+
+	// Statut global : même filtrage, seul le projet avec profils interroge compose
+	projects := []project.Project{*testProject(), {Name: "blog"}}
+	fake.calls = nil
+	mgr.LoadStatuses(context.Background(), projects)
+	if projects[0].ServiceCount != 1 || !projects[0].Running {
+		t.Errorf("web : %d containers, running=%v, attendu 1 (profil inactif exclu)", projects[0].ServiceCount, projects[0].Running)
+	}
+	if projects[1].ServiceCount != 1 {
+		t.Errorf("blog : %d containers, attendu 1 (sans profil, tous les services comptent)", projects[1].ServiceCount)
+	}
+	if len(fake.calls) != 1 || fake.calls[0].Dir != testProject().Path {
+		t.Errorf("appels compose = %+v, attendu un seul config --services pour web", fake.calls)
+	}
+}
+
+func TestComposeArgsProfiles(t *testing.T) {
+	p := &project.Project{Name: "web", Profiles: []string{"debug", "tools"}}
+	got := (&Manager{}).composeArgs(p, "ps")
+	want := []string{"-f", "docker-compose.yml", "-p", "web", "--profile", "debug", "--profile", "tools", "ps"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("composeArgs = %q, attendu %q", got, want)
+	}
+}
+
+func TestEachProject(t *testing.T) {
+	projects := make([]project.Project, 10)
+	for i := range projects {
+		projects[i].Name = fmt.Sprintf("p%d", i)
+	}
+	mgr := &Manager{StatusWorkers: 3, Timeouts: Timeouts{Query: time.Minute}}
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	mgr.eachProject(context.Background(), projects, func(ctx context.Context, p *project.Project) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("%s : pas de timeout par projet", p.Name)
+		}
+		time.Sleep(5 * time.Millisecond)
+		p.Running = true
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+
+	if peak > 3 {
+		t.Errorf("%d projets interrogés simultanément, attendu 3 au plus", peak)
+	}
+	for i, p := range projects {
+		if p.Name != fmt.Sprintf("p%d", i) || !p.Running {
+			t.Errorf("projet %d = %+v, attendu p%d traité à sa place", i, p, i)
+		}
+	}
+
+	// Aucun projet : aucun worker, pas de blocage
+	mgr.eachProject(context.Background(), nil, func(ctx context.Context, p *project.Project) {
+		t.Error("fn appelée sans projet")
+	})
+}
@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/phil/docker-manager/pkg/config"
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// Task est une commande ponctuelle nommée, exécutée avec compose run --rm
+type Task struct {
+	Name        string
+	Description string
+	Service     string
+	Command     []string
+	Env         map[string]string
+	Workdir     string
+}
+
+// TaskFromConfig convertit une tâche définie dans projects.yml
+func TaskFromConfig(name string, cfg config.TaskConfig) Task {
+	return Task{
+		Name:        name,
+		Description: cfg.Description,
+		Service:     cfg.Service,
+		Command:     append([]string(nil), cfg.Command...),
+		Env:         cfg.Env,
+		Workdir:     cfg.Workdir,
+	}
+}
+
+// TasksFromConfig retourne les tâches d'un projet, triées par nom
+func TasksFromConfig(cfg config.ProjectConfig) []Task {
+	tasks := make([]Task, 0, len(cfg.Tasks))
+	for name, taskCfg := range cfg.Tasks {
+		tasks = append(tasks, TaskFromConfig(name, taskCfg))
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Name < tasks[j].Name
+	})
+	return tasks
+}
+
+// isShellCommand indique si la commande a été définie sous forme de chaîne (sh -c)
+func (t Task) isShellCommand() bool {
+	return len(t.Command) == 3 && t.Command[0] == "sh" && t.Command[1] == "-c"
+}
+
+// CommandString retourne la commande lisible de la tâche
+func (t Task) CommandString() string {
+	if t.isShellCommand() {
+		return t.Command[2]
+	}
+	return strings.Join(t.Command, " ")
+}
+
+// commandWith ajoute les arguments à la commande. Pour une commande sh -c,
+// ils sont transmis au script via "$@" (ajouté à la fin du script).
+func (t Task) commandWith(extraArgs []string) []string {
+	command := append([]string(nil), t.Command...)
+	if len(extraArgs) == 0 {
+		return command
+	}
+	if t.isShellCommand() {
+		command[2] += ` "$@"`
+		command = append(command, "sh")
+	}
+	return append(command, extraArgs...)
+}
+
+// RunTask exécute une tâche dans un container éphémère (compose run --rm).
+// Les arguments supplémentaires sont ajoutés à la commande de la tâche ;
+// le workdir de la tâche est utilisé si opts.Workdir est vide.
+func (m *Manager) RunTask(ctx context.Context, p *project.Project, task Task, extraArgs []string, opts ExecOptions) error {
+	if task.Service == "" {
+		return fmt.Errorf("la tâche %s n'a pas de service", task.Name)
+	}
+
+	args := []string{"run", "--rm"}
+	if !opts.TTY {
+		args = append(args, "-T")
+	}
+	if opts.User != "" {
+		args = append(args, "--user", opts.User)
+	}
+	workdir := opts.Workdir
+	if workdir == "" {
+		workdir = task.Workdir
+	}
+	if workdir != "" {
+		args = append(args, "--workdir", workdir)
+	}
+
+	keys := make([]string, 0, len(task.Env))
+	for key := range task.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "-e", key+"="+task.Env[key])
+	}
+
+	args = append(args, task.Service)
+	args = append(args, task.commandWith(extraArgs)...)
+
+	m.report(p, "run", false, "▶️  Tâche %s (%s) : %s", task.Name, task.Service, task.CommandString())
+	inv := m.invocation(p, args...)
+	inv.Stdin = m.Stdin
+	inv.Stdout = m.Stdout
+	inv.Stderr = m.Stderr
+	if err := stepErr(ctx, "run", m.Runner.Run(ctx, inv)); err != nil {
+		return fmt.Errorf("tâche %s échouée: %w", task.Name, err)
+	}
+
+	m.report(p, "run", true, "✅ Tâche %s terminée", task.Name)
+	return nil
+}
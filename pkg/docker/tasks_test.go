@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/config"
+)
+
+func TestRunTaskArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  Task
+		extra []string
+		opts  ExecOptions
+		want  []string
+	}{
+		{
+			name: "liste",
+			task: Task{Name: "build", Service: "node", Command: []string{"npm", "run", "build"}},
+			want: []string{"run", "--rm", "-T", "node", "npm", "run", "build"},
+		},
+		{
+			name:  "liste + arguments",
+			task:  Task{Name: "test", Service: "app", Command: []string{"go", "test"}},
+			extra: []string{"./pkg/..."},
+			want:  []string{"run", "--rm", "-T", "app", "go", "test", "./pkg/..."},
+		},
+		{
+			name:  "sh -c + arguments",
+			task:  Task{Name: "migrate", Service: "app", Command: []string{"sh", "-c", "php artisan migrate"}},
+			extra: []string{"--force"},
+			want:  []string{"run", "--rm", "-T", "app", "sh", "-c", `php artisan migrate "$@"`, "sh", "--force"},
+		},
+		{
+			name: "env triées, workdir de la tâche, tty",
+			task: Task{Name: "seed", Service: "app", Command: []string{"seed"}, Workdir: "/app",
+				Env: map[string]string{"B": "2", "A": "1"}},
+			opts: ExecOptions{TTY: true, User: "root"},
+			want: []string{"run", "--rm", "--user", "root", "--workdir", "/app", "-e", "A=1", "-e", "B=2", "app", "seed"},
+		},
+		{
+			name: "workdir de la ligne de commande",
+			task: Task{Name: "ls", Service: "app", Command: []string{"ls"}, Workdir: "/app"},
+			opts: ExecOptions{Workdir: "/tmp"},
+			want: []string{"run", "--rm", "-T", "--workdir", "/tmp", "app", "ls"},
+		},
+	}
+
+	for _, tt := range tests {
+		fake := &fakeRunner{}
+		mgr := &Manager{Runner: fake.runner()}
+		if err := mgr.RunTask(context.Background(), testProject(), tt.task, tt.extra, tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		if got := fake.commands(t, testBase); !reflect.DeepEqual(got, [][]string{tt.want}) {
+			t.Errorf("%s : commandes = %q, attendu %q", tt.name, got, tt.want)
+		}
+	}
+
+	if err := (&Manager{}).RunTask(context.Background(), testProject(), Task{Name: "vide"}, nil, ExecOptions{}); err == nil {
+		t.Error("tâche sans service : erreur attendue")
+	}
+}
+
+func TestTasksFromConfig(t *testing.T) {
+	cfg := config.ProjectConfig{Tasks: map[string]config.TaskConfig{
+		"migrate": {Service: "app", Command: config.CommandLine{"sh", "-c", "php artisan migrate"}},
+		"build":   {Service: "node", Command: config.CommandLine{"npm", "run", "build"}},
+	}}
+
+	tasks := TasksFromConfig(cfg)
+	if len(tasks) != 2 || tasks[0].Name != "build" || tasks[1].Name != "migrate" {
+		t.Fatalf("tâches = %+v, attendu build puis migrate", tasks)
+	}
+	if got := tasks[0].CommandString(); got != "npm run build" {
+		t.Errorf("build : %q", got)
+	}
+	if got := tasks[1].CommandString(); got != "php artisan migrate" {
+		t.Errorf("migrate : %q, attendu le script sans sh -c", got)
+	}
+}
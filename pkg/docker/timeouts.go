@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/config"
+)
+
+// Timeouts définit la durée maximale de chaque type d'opération.
+// Une durée nulle désactive le timeout (seule l'annulation du contexte s'applique).
+type Timeouts struct {
+	Query  time.Duration // ps, config, inspect, API Engine
+	Build  time.Duration // compose build
+	Up     time.Duration // compose up
+	Down   time.Duration // compose down, stop, restart
+	Daemon time.Duration // docker --version, docker info
+}
+
+// DefaultTimeouts retourne les timeouts par défaut
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Query:  10 * time.Second,
+		Build:  30 * time.Minute,
+		Up:     5 * time.Minute,
+		Down:   2 * time.Minute,
+		Daemon: 10 * time.Second,
+	}
+}
+
+// TimeoutsFromConfig applique les durées de la config sur les valeurs par défaut.
+// Une durée invalide garde sa valeur par défaut et est signalée dans l'erreur,
+// les autres durées restent appliquées.
+func TimeoutsFromConfig(cfg config.TimeoutsConfig) (Timeouts, error) {
+	timeouts := DefaultTimeouts()
+	fields := []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"query", cfg.Query, &timeouts.Query},
+		{"build", cfg.Build, &timeouts.Build},
+		{"up", cfg.Up, &timeouts.Up},
+		{"down", cfg.Down, &timeouts.Down},
+		{"daemon", cfg.Daemon, &timeouts.Daemon},
+	}
+
+	var errs []error
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(field.value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("timeout %s invalide (%q): %w", field.name, field.value, err))
+			continue
+		}
+		*field.dest = duration
+	}
+	return timeouts, errors.Join(errs...)
+}
+
+// ConfiguredTimeouts retourne les timeouts définis dans la config. En cas de
+// config illisible ou de durée invalide, l'erreur est retournée avec les
+// timeouts utilisables (valeurs par défaut pour ce qui n'a pu être lu).
+func ConfiguredTimeouts() (Timeouts, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return DefaultTimeouts(), err
+	}
+	return TimeoutsFromConfig(cfg.Timeouts)
+}
+
+// forStep retourne le timeout associé à une sous-commande compose
+func (t Timeouts) forStep(step string) time.Duration {
+	switch step {
+	case "build", "pull":
+		return t.Build
+	case "up":
+		return t.Up
+	case "down", "stop", "restart", "rm":
+		return t.Down
+	default:
+		return t.Query
+	}
+}
+
+// withTimeout dérive un contexte borné (ou simplement annulable si timeout nul)
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// StepError indique qu'une étape a été interrompue (Ctrl-C) ou a dépassé son timeout
+type StepError struct {
+	Step string
+	Err  error
+}
+
+// Error décrit l'étape concernée et la cause de l'interruption
+func (e *StepError) Error() string {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return fmt.Sprintf("étape '%s' : délai dépassé", e.Step)
+	}
+	return fmt.Sprintf("étape '%s' interrompue", e.Step)
+}
+
+// Unwrap retourne context.Canceled ou context.DeadlineExceeded
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// stepErr remplace l'erreur du processus par une StepError si le contexte est terminé
+func stepErr(ctx context.Context, step string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &StepError{Step: step, Err: ctxErr}
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/config"
+)
+
+func TestTimeoutsFromConfig(t *testing.T) {
+	timeouts, err := TimeoutsFromConfig(config.TimeoutsConfig{Up: "10m", Daemon: "0s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultTimeouts()
+	want.Up = 10 * time.Minute
+	want.Daemon = 0
+	if timeouts != want {
+		t.Errorf("timeouts = %+v, attendu %+v", timeouts, want)
+	}
+}
+
+func TestTimeoutsFromConfigInvalid(t *testing.T) {
+	timeouts, err := TimeoutsFromConfig(config.TimeoutsConfig{Up: "5 minutes", Down: "30s", Build: "long"})
+	if err == nil {
+		t.Fatal("erreur attendue pour up et build")
+	}
+	for _, field := range []string{"timeout up invalide", "timeout build invalide"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("erreur = %q, attendu %q", err, field)
+		}
+	}
+
+	// Les durées valides restent appliquées, les invalides gardent leur défaut
+	want := DefaultTimeouts()
+	want.Down = 30 * time.Second
+	if timeouts != want {
+		t.Errorf("timeouts = %+v, attendu %+v", timeouts, want)
+	}
+}
+
+func TestTimeoutsForStep(t *testing.T) {
+	timeouts := Timeouts{Query: 1, Build: 2, Up: 3, Down: 4}
+	tests := []struct {
+		step string
+		want time.Duration
+	}{
+		{"build", 2},
+		{"pull", 2},
+		{"up", 3},
+		{"down", 4},
+		{"stop", 4},
+		{"restart", 4},
+		{"rm", 4},
+		{"ps", 1},
+		{"config", 1},
+	}
+	for _, tt := range tests {
+		if got := timeouts.forStep(tt.step); got != tt.want {
+			t.Errorf("forStep(%q) = %v, attendu %v", tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestStepError(t *testing.T) {
+	cause := errors.New("signal: interrupt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := stepErr(ctx, "up", cause)
+	if err == nil || err.Error() != "étape 'up' interrompue" || !errors.Is(err, context.Canceled) {
+		t.Errorf("annulation : erreur = %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	err = stepErr(ctx, "build", cause)
+	if err == nil || err.Error() != "étape 'build' : délai dépassé" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout : erreur = %v", err)
+	}
+
+	// Contexte actif : l'erreur du processus est conservée
+	if err := stepErr(context.Background(), "up", cause); err != cause {
+		t.Errorf("erreur = %v, attendu %v", err, cause)
+	}
+	if err := stepErr(ctx, "up", nil); err != nil {
+		t.Errorf("succès : erreur = %v", err)
+	}
+}
+
+func TestConfiguredTimeoutsReportsInvalidConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfig(t, home, "timeouts:\n  up: 5 minutes\n  query: 3s\n")
+
+	timeouts, err := ConfiguredTimeouts()
+	if err == nil || !strings.Contains(err.Error(), "timeout up invalide") {
+		t.Errorf("erreur = %v, attendu le timeout up invalide", err)
+	}
+	if timeouts.Query != 3*time.Second || timeouts.Up != DefaultTimeouts().Up {
+		t.Errorf("timeouts = %+v", timeouts)
+	}
+}
+
+// writeConfig écrit un projects.yml dans le HOME de test
+func writeConfig(t *testing.T, home, content string) {
+	t.Helper()
+	dir := filepath.Join(home, ".docker-manager")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "projects.yml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
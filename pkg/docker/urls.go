@@ -0,0 +1,239 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// Endpoint est un port publié par un service sur l'hôte
+type Endpoint struct {
+	Host          string // localhost pour 0.0.0.0 et ::, sinon l'IP liée
+	Port          string // port de l'hôte (ou plage, ex: 8000-8002)
+	ContainerPort string
+	Proto         string // tcp ou udp
+	URL           string // vide si le port n'est pas HTTP (base de données, UDP...)
+}
+
+// Address retourne hôte:port ([::1]:8080 en IPv6)
+func (e Endpoint) Address() string {
+	return net.JoinHostPort(e.Host, e.Port)
+}
+
+// String retourne l'URL, sinon hôte:port (suffixé /udp pour un port UDP)
+func (e Endpoint) String() string {
+	if e.URL != "" {
+		return e.URL
+	}
+	if e.Proto == "udp" {
+		return e.Address() + "/udp"
+	}
+	return e.Address()
+}
+
+// httpsPorts sont les ports servis en https par défaut
+var httpsPorts = map[string]bool{"443": true, "8443": true}
+
+// nonHTTPPorts sont les ports de services connus qui ne parlent pas HTTP :
+// ils sont listés en hôte:port plutôt qu'en URL
+var nonHTTPPorts = map[string]bool{
+	"21": true, "22": true, "25": true, "53": true, "465": true, "587": true,
+	"1433": true, "1521": true, "3306": true, "5432": true, "5672": true,
+	"6379": true, "11211": true, "27017": true,
+}
+
+// GetServiceEndpoints retourne une map service -> ports publiés sur l'hôte,
+// avec une URL pour les ports HTTP (réglages scheme, path et hostname du
+// service dans projects.yml)
+func (m *Manager) GetServiceEndpoints(ctx context.Context, p *project.Project) (map[string][]Endpoint, error) {
+	bindingsByService := make(map[string][]portBinding)
+
+	if containers, err := m.engineContainers(ctx, p, false); err == nil {
+		for _, container := range containers {
+			service := container.Service()
+			bindingsByService[service] = append(bindingsByService[service], engineBindings(container.Ports)...)
+		}
+	} else {
+		active := m.activeServices(ctx, p)
+		queryCtx, cancel := withTimeout(ctx, m.Timeouts.Query)
+		defer cancel()
+
+		output, err := dockerOutput(
+			queryCtx,
+			"ps",
+			"--filter",
+			fmt.Sprintf("label=%s=%s", LabelProject, p.Name),
+			"--filter",
+			fmt.Sprintf("label=%s=False", LabelOneOff),
+			"--format",
+			fmt.Sprintf("{{.Label \"%s\"}}\t{{.Ports}}", LabelService),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la recuperation des ports: %w", err)
+		}
+
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			parts := strings.SplitN(strings.TrimSpace(line), "\t", 2)
+			if len(parts) < 2 {
+				continue
+			}
+			service := strings.TrimSpace(parts[0])
+			if active != nil && !active[service] {
+				continue
+			}
+			bindingsByService[service] = append(bindingsByService[service], parsePorts(parts[1])...)
+		}
+	}
+
+	endpointsByService := make(map[string][]Endpoint)
+	for service, bindings := range bindingsByService {
+		if endpoints := bindingsToEndpoints(bindings, p.URLSettings[service]); len(endpoints) > 0 {
+			endpointsByService[service] = endpoints
+		}
+	}
+	return endpointsByService, nil
+}
+
+// GetServiceURLs retourne une map service -> URLs HTTP(S) exposées
+func (m *Manager) GetServiceURLs(ctx context.Context, p *project.Project) (map[string][]string, error) {
+	endpoints, err := m.GetServiceEndpoints(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	urlsByService := make(map[string][]string)
+	for service, list := range endpoints {
+		for _, endpoint := range list {
+			if endpoint.URL != "" {
+				urlsByService[service] = append(urlsByService[service], endpoint.URL)
+			}
+		}
+	}
+	return urlsByService, nil
+}
+
+// PrimaryURL retourne l'URL à ouvrir pour un projet : la première URL du
+// service demandé, sinon l'URL configurée (url dans projects.yml), sinon
+// la première URL du premier service (par ordre alphabétique)
+func (m *Manager) PrimaryURL(ctx context.Context, p *project.Project, service string) (string, error) {
+	if service == "" && p.URL != "" {
+		return p.URL, nil
+	}
+
+	urls, err := m.GetServiceURLs(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	if service != "" {
+		if len(urls[service]) == 0 {
+			return "", fmt.Errorf("le service %s de %s ne publie aucune URL", service, p.Name)
+		}
+		return urls[service][0], nil
+	}
+
+	services := make([]string, 0, len(urls))
+	for name := range urls {
+		services = append(services, name)
+	}
+	if len(services) == 0 {
+		return "", fmt.Errorf("%s ne publie aucune URL (projet arrêté ?)", p.Name)
+	}
+	sort.Strings(services)
+	return urls[services[0]][0], nil
+}
+
+// bindingsToEndpoints convertit les ports publiés d'un service en endpoints
+// triés. Une même liaison en IPv4 et IPv6 (0.0.0.0 et ::) n'apparaît qu'une fois.
+func bindingsToEndpoints(bindings []portBinding, settings project.URLSettings) []Endpoint {
+	var endpoints []Endpoint
+	seen := make(map[string]bool)
+
+	for _, binding := range bindings {
+		if binding.HostPort == "" {
+			continue
+		}
+		proto := binding.Proto
+		if proto == "" {
+			proto = "tcp"
+		}
+
+		endpoint := Endpoint{
+			Host:          bindingHost(binding.HostIP),
+			Port:          binding.HostPort,
+			ContainerPort: binding.ContainerPort,
+			Proto:         proto,
+		}
+		key := endpoint.Address() + "/" + proto
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		endpoint.URL = endpointURL(endpoint, settings)
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if (endpoints[i].URL == "") != (endpoints[j].URL == "") {
+			return endpoints[i].URL != ""
+		}
+		return endpoints[i].String() < endpoints[j].String()
+	})
+	return endpoints
+}
+
+// bindingHost retourne l'hôte à utiliser pour joindre un port publié
+func bindingHost(ip string) string {
+	if isWildcardIP(ip) {
+		return "localhost"
+	}
+	return ip
+}
+
+// endpointURL construit l'URL d'un port publié, vide s'il ne parle pas HTTP.
+// Sans scheme configuré, 443 et 8443 sont en https et les ports connus
+// d'autres protocoles (5432, 6379...) n'ont pas d'URL.
+func endpointURL(endpoint Endpoint, settings project.URLSettings) string {
+	if endpoint.Proto != "tcp" || strings.Contains(endpoint.Port, "-") {
+		return ""
+	}
+
+	scheme := settings.Scheme
+	if scheme == "" {
+		switch {
+		case httpsPorts[endpoint.ContainerPort] || httpsPorts[endpoint.Port]:
+			scheme = "https"
+		case nonHTTPPorts[endpoint.ContainerPort]:
+			return ""
+		default:
+			scheme = "http"
+		}
+	}
+	if scheme != "http" && scheme != "https" {
+		return ""
+	}
+
+	host := endpoint.Host
+	if settings.Hostname != "" {
+		host = settings.Hostname
+	}
+
+	address := net.JoinHostPort(host, endpoint.Port)
+	if (scheme == "http" && endpoint.Port == "80") || (scheme == "https" && endpoint.Port == "443") {
+		address = host
+		if strings.Contains(host, ":") {
+			address = "[" + host + "]"
+		}
+	}
+
+	path := settings.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return scheme + "://" + address + path
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+func TestBindingsToEndpoints(t *testing.T) {
+	bindings := []portBinding{
+		{HostIP: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Proto: "tcp"},
+		// Même liaison en IPv6 : dédoublonnée
+		{HostIP: "::", HostPort: "8080", ContainerPort: "80", Proto: "tcp"},
+		{HostIP: "127.0.0.1", HostPort: "8443", ContainerPort: "443", Proto: "tcp"},
+		{HostIP: "::1", HostPort: "9000", ContainerPort: "9000"},
+		{HostIP: "0.0.0.0", HostPort: "5432", ContainerPort: "5432", Proto: "tcp"},
+		{HostIP: "0.0.0.0", HostPort: "53", ContainerPort: "53", Proto: "udp"},
+		{HostIP: "0.0.0.0", HostPort: "8000-8002", ContainerPort: "8000-8002", Proto: "tcp"},
+		// Port exposé mais non publié
+		{ContainerPort: "3000", Proto: "tcp"},
+	}
+
+	var got []string
+	for _, endpoint := range bindingsToEndpoints(bindings, project.URLSettings{}) {
+		got = append(got, endpoint.String())
+	}
+	want := []string{
+		"http://[::1]:9000",
+		"http://localhost:8080",
+		"https://127.0.0.1:8443",
+		"localhost:53/udp",
+		"localhost:5432",
+		"localhost:8000-8002",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %q, attendu %q", got, want)
+	}
+}
+
+func TestEndpointURL(t *testing.T) {
+	endpoint := func(host, port, containerPort, proto string) Endpoint {
+		return Endpoint{Host: host, Port: port, ContainerPort: containerPort, Proto: proto}
+	}
+
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		settings project.URLSettings
+		want     string
+	}{
+		{"http", endpoint("localhost", "8080", "80", "tcp"), project.URLSettings{}, "http://localhost:8080"},
+		{"port 80 omis", endpoint("localhost", "80", "80", "tcp"), project.URLSettings{}, "http://localhost"},
+		{"port 443 omis", endpoint("localhost", "443", "443", "tcp"), project.URLSettings{}, "https://localhost"},
+		{"443 dans le container", endpoint("localhost", "8081", "443", "tcp"), project.URLSettings{}, "https://localhost:8081"},
+		{"8443 sur l'hôte", endpoint("localhost", "8443", "8080", "tcp"), project.URLSettings{}, "https://localhost:8443"},
+		{"IP spécifique", endpoint("192.168.1.10", "8080", "80", "tcp"), project.URLSettings{}, "http://192.168.1.10:8080"},
+		{"IPv6", endpoint("::1", "8080", "80", "tcp"), project.URLSettings{}, "http://[::1]:8080"},
+		{"IPv6 port 80 omis", endpoint("::1", "80", "80", "tcp"), project.URLSettings{}, "http://[::1]"},
+		{"postgres", endpoint("localhost", "15432", "5432", "tcp"), project.URLSettings{}, ""},
+		{"redis", endpoint("localhost", "6379", "6379", "tcp"), project.URLSettings{}, ""},
+		{"udp", endpoint("localhost", "8080", "80", "udp"), project.URLSettings{}, ""},
+		{"plage", endpoint("localhost", "8000-8002", "8000-8002", "tcp"), project.URLSettings{}, ""},
+		{"scheme forcé", endpoint("localhost", "15432", "5432", "tcp"), project.URLSettings{Scheme: "http"}, "http://localhost:15432"},
+		{"https forcé", endpoint("localhost", "8080", "80", "tcp"), project.URLSettings{Scheme: "https"}, "https://localhost:8080"},
+		{"scheme tcp", endpoint("localhost", "8080", "80", "tcp"), project.URLSettings{Scheme: "tcp"}, ""},
+		{"hostname", endpoint("localhost", "80", "80", "tcp"), project.URLSettings{Hostname: "app.test"}, "http://app.test"},
+		{"hostname et port", endpoint("localhost", "8080", "80", "tcp"), project.URLSettings{Hostname: "app.test"}, "http://app.test:8080"},
+		{"path", endpoint("localhost", "8080", "80", "tcp"), project.URLSettings{Path: "/admin"}, "http://localhost:8080/admin"},
+		{"path sans /", endpoint("localhost", "8080", "80", "tcp"), project.URLSettings{Path: "health"}, "http://localhost:8080/health"},
+	}
+	for _, tt := range tests {
+		if got := endpointURL(tt.endpoint, tt.settings); got != tt.want {
+			t.Errorf("%s : %q, attendu %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrimaryURL(t *testing.T) {
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Container{
+			{ID: "1", Labels: composeLabels("web", "app"), Ports: []Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}}},
+			{ID: "2", Labels: composeLabels("web", "admin"), Ports: []Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8081, Type: "tcp"}}},
+			{ID: "3", Labels: composeLabels("web", "db"), Ports: []Port{{IP: "0.0.0.0", PrivatePort: 5432, PublicPort: 5432, Type: "tcp"}}},
+		})
+	}))
+	fake := &fakeRunner{output: func(args []string) string { return "admin\napp\ndb\n" }}
+	mgr := &Manager{Runner: fake.runner(), Engine: engine}
+
+	tests := []struct {
+		name       string
+		configured string
+		service    string
+		want       string
+		wantErr    bool
+	}{
+		{"premier service", "", "", "http://localhost:8081", false},
+		{"url configurée", "https://web.test", "", "https://web.test", false},
+		{"service demandé", "https://web.test", "app", "http://localhost:8080", false},
+		{"service sans URL", "", "db", "", true},
+	}
+	for _, tt := range tests {
+		p := testProject()
+		p.URL = tt.configured
+		got, err := mgr.PrimaryURL(context.Background(), p, tt.service)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s : %q, %v, attendu %q", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phil/docker-manager/pkg/config"
+	"github.com/phil/docker-manager/pkg/health"
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// DefaultWaitTimeout est la durée d'attente par défaut de la santé des services
+const DefaultWaitTimeout = 2 * time.Minute
+
+// waitInterval sépare deux vérifications de l'état des services
+const waitInterval = 2 * time.Second
+
+// WaitOptions contrôle WaitHealthy
+type WaitOptions struct {
+	Services []string          // services attendus (tous ceux qui ont un container si vide)
+	Timeout  time.Duration     // DefaultWaitTimeout si nul
+	Checks   map[string]string // service -> health_check de projects.yml
+	// Restarted donne le démarrage (StartedAt) de chaque container avant un
+	// restart : tant qu'il n'a pas changé, l'état affiché est celui d'avant
+	Restarted map[string]time.Time
+}
+
+// HealthChecksFromConfig retourne les health_check configurés par service
+func HealthChecksFromConfig(services []config.ServiceConfig) map[string]string {
+	checks := make(map[string]string)
+	for _, service := range services {
+		if service.HealthCheck != "" {
+			checks[service.Name] = service.HealthCheck
+		}
+	}
+	return checks
+}
+
+// ServiceFailure explique pourquoi un service n'est pas en bonne santé
+type ServiceFailure struct {
+	Service string
+	Reason  string
+}
+
+// HealthError est retournée quand des services ne sont pas sains à temps
+type HealthError struct {
+	Project  string
+	Timeout  time.Duration
+	Failures []ServiceFailure
+}
+
+// Error liste chaque service en échec et la raison
+func (e *HealthError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d service(s) de %s non opérationnel(s) après %s :", len(e.Failures), e.Project, e.Timeout)
+	for _, failure := range e.Failures {
+		fmt.Fprintf(&b, "\n  - %s : %s", failure.Service, failure.Reason)
+	}
+	return b.String()
+}
+
+// serviceHealth est le résultat d'une vérification d'un service
+type serviceHealth struct {
+	ready  bool
+	final  bool // échec définitif (container arrêté en erreur)
+	reason string
+}
+
+// WaitHealthy attend que chaque service soit opérationnel : healthcheck Docker
+// "healthy" ou health_check configuré réussi ; à défaut de check, un container
+// démarré suffit. Un container sorti avec le code 0 (tâche d'init) est accepté.
+// Retourne une *HealthError détaillant les services en échec.
+func (m *Manager) WaitHealthy(ctx context.Context, p *project.Project, opts WaitOptions) error {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	checks := make(map[string]health.Check)
+	for service, value := range opts.Checks {
+		check, err := health.Parse(value)
+		if err != nil {
+			return fmt.Errorf("health_check du service %s: %w", service, err)
+		}
+		checks[service] = check
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	m.report(p, "wait", false, "🩺 Attente de la santé des services de %s (max %s)...", p.Name, timeout)
+	wanted := make(map[string]bool, len(opts.Services))
+	for _, service := range opts.Services {
+		wanted[service] = true
+	}
+
+	lastPending := ""
+	for {
+		results, err := m.checkServices(waitCtx, p, wanted, checks, opts.Restarted)
+		if err != nil && waitCtx.Err() == nil {
+			return err
+		}
+
+		var pending []string
+		var failures []ServiceFailure
+		for _, name := range sortedKeys(results) {
+			result := results[name]
+			if result.ready {
+				continue
+			}
+			failures = append(failures, ServiceFailure{Service: name, Reason: result.reason})
+			if !result.final {
+				pending = append(pending, fmt.Sprintf("%s (%s)", name, result.reason))
+			}
+		}
+
+		switch {
+		case err == nil && len(failures) == 0:
+			m.report(p, "wait", true, "✅ Tous les services de %s sont opérationnels", p.Name)
+			return nil
+		case err == nil && len(pending) == 0:
+			// Seulement des échecs définitifs : inutile d'attendre
+			return &HealthError{Project: p.Name, Timeout: timeout, Failures: failures}
+		}
+
+		if summary := strings.Join(pending, ", "); summary != lastPending {
+			m.report(p, "wait", false, "⏳ En attente : %s", summary)
+			lastPending = summary
+		}
+
+		select {
+		case <-ctx.Done():
+			return &StepError{Step: "wait", Err: ctx.Err()}
+		case <-waitCtx.Done():
+			if len(failures) == 0 {
+				failures = []ServiceFailure{{Service: p.Name, Reason: "état des services indisponible"}}
+			}
+			return &HealthError{Project: p.Name, Timeout: timeout, Failures: failures}
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// checkServices vérifie une fois chaque service attendu
+func (m *Manager) checkServices(ctx context.Context, p *project.Project, wanted map[string]bool, checks map[string]health.Check, restarted map[string]time.Time) (map[string]serviceHealth, error) {
+	statuses, err := m.GetServiceStatuses(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]serviceHealth)
+	for _, service := range statuses {
+		if len(wanted) > 0 && !wanted[service.Name] {
+			continue
+		}
+		if len(wanted) == 0 && service.Status == "" {
+			// Service sans container (profil inactif, tâche ponctuelle)
+			continue
+		}
+		if previous, ok := restarted[service.Container]; ok && service.Status == "running" && service.StartedAt.Equal(previous) {
+			// Santé d'avant le restart : pas encore significative
+			results[service.Name] = serviceHealth{reason: "redémarrage en cours"}
+			continue
+		}
+		results[service.Name] = m.serviceHealth(ctx, p, service, checks)
+	}
+	return results, nil
+}
+
+// serviceHealth détermine si un service est opérationnel
+func (m *Manager) serviceHealth(ctx context.Context, p *project.Project, service project.Service, checks map[string]health.Check) serviceHealth {
+	switch service.Status {
+	case "running":
+	case "exited", "dead":
+		if service.ExitCode == 0 && service.Status == "exited" {
+			return serviceHealth{ready: true}
+		}
+		return serviceHealth{final: true, reason: service.StateString()}
+	case "restarting":
+		return serviceHealth{reason: fmt.Sprintf("redémarre en boucle, dernier code %d", service.ExitCode)}
+	case "":
+		return serviceHealth{reason: "aucun container"}
+	default:
+		return serviceHealth{reason: service.StateString()}
+	}
+
+	// Healthcheck Docker : suffisant s'il est passé
+	if service.Health == "healthy" {
+		return serviceHealth{ready: true}
+	}
+
+	check, configured := checks[service.Name]
+	if !configured {
+		if service.Health != "" {
+			return serviceHealth{reason: "healthcheck Docker " + service.Health}
+		}
+		return serviceHealth{ready: true}
+	}
+
+	if err := m.runCheck(ctx, p, service.Name, check); err != nil {
+		reason := err.Error()
+		if service.Health != "" {
+			reason = fmt.Sprintf("healthcheck Docker %s, %s", service.Health, reason)
+		}
+		return serviceHealth{reason: reason}
+	}
+	return serviceHealth{ready: true}
+}
+
+// runCheck exécute un health_check configuré (HTTP, TCP ou commande dans le container)
+func (m *Manager) runCheck(ctx context.Context, p *project.Project, service string, check health.Check) error {
+	ctx, cancel := context.WithTimeout(ctx, health.DefaultTimeout)
+	defer cancel()
+
+	switch check.Kind {
+	case health.KindHTTP:
+		return health.CheckHTTP(ctx, check.Target)
+	case health.KindTCP:
+		return health.CheckTCP(ctx, check.Target)
+	default:
+		var stderr strings.Builder
+		inv := m.invocation(p, "exec", "-T", service, "sh", "-c", check.Target)
+		inv.Stdout = io.Discard
+		inv.Stderr = &stderr
+		if err := m.Runner.Run(ctx, inv); err != nil {
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				return fmt.Errorf("%q a échoué: %s", check.Target, lastLine(message))
+			}
+			return fmt.Errorf("%q a échoué: %w", check.Target, err)
+		}
+		return nil
+	}
+}
+
+// lastLine retourne la dernière ligne d'une sortie
+func lastLine(output string) string {
+	lines := strings.Split(output, "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// sortedKeys retourne les noms de services triés
+func sortedKeys(results map[string]serviceHealth) []string {
+	keys := make([]string, 0, len(results))
+	for key := range results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
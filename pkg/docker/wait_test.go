@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRestartWaitIgnoresStaleHealth(t *testing.T) {
+	var inspections int32
+	engine := newFakeEngine(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/containers/json" {
+			json.NewEncoder(w).Encode([]Container{
+				{ID: "aaaaaaaaaaaa", State: "running", Labels: map[string]string{LabelProject: "web", LabelService: "app"}},
+			})
+			return
+		}
+		// 1 : avant le restart, 2 : première vérification, état encore inchangé
+		startedAt := "2026-01-01T10:00:00Z"
+		if atomic.AddInt32(&inspections, 1) > 2 {
+			startedAt = "2026-01-01T10:05:00Z"
+		}
+		fmt.Fprintf(w, `{"Id":"aaaaaaaaaaaa","State":{"Status":"running","Running":true,"StartedAt":%q,"Health":{"Status":"healthy"}},
+			"Config":{"Labels":{"com.docker.compose.project":"web","com.docker.compose.service":"app"}}}`, startedAt)
+	}))
+
+	fake := &fakeRunner{}
+	mgr := &Manager{Engine: engine, Runner: fake.runner()}
+	p := testProject()
+	p.Profiles = nil
+
+	if err := mgr.RestartProject(context.Background(), p, RestartOptions{Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&inspections); got < 3 {
+		t.Errorf("santé acceptée après %d inspection(s), avant le nouveau démarrage du container", got)
+	}
+}
@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// closedAddress retourne une adresse locale sur laquelle rien n'écoute
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("écoute locale impossible: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	return address
+}
+
+// statusServer répond le statut demandé par le chemin (/200, /503...)
+func statusServer(t *testing.T, tls bool) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/503":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/302":
+			// Redirection vers une page saine : suivie par le client
+			http.Redirect(w, r, "/200", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	var server *httptest.Server
+	if tls {
+		server = httptest.NewTLSServer(handler)
+	} else {
+		server = httptest.NewServer(handler)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantKind   Kind
+		wantTarget string
+		wantErr    bool
+	}{
+		{"http://localhost:8080/health", KindHTTP, "http://localhost:8080/health", false},
+		{" https://app.test ", KindHTTP, "https://app.test", false},
+		{"tcp://localhost:5432", KindTCP, "localhost:5432", false},
+		{"pg_isready -U postgres", KindCommand, "pg_isready -U postgres", false},
+		{"", "", "", true},
+		{"tcp://localhost", "", "", true},
+		{"http://[::1", "", "", true},
+	}
+	for _, tt := range tests {
+		check, err := Parse(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) : erreur = %v", tt.value, err)
+			continue
+		}
+		if check.Kind != tt.wantKind || check.Target != tt.wantTarget {
+			t.Errorf("Parse(%q) = %+v, attendu %s %q", tt.value, check, tt.wantKind, tt.wantTarget)
+		}
+	}
+
+	// String restitue la valeur configurée
+	if check, _ := Parse("tcp://db:5432"); check.String() != "tcp://db:5432" {
+		t.Errorf("String() = %q", check.String())
+	}
+}
+
+func TestStatusOK(t *testing.T) {
+	for status, want := range map[int]bool{0: false, 200: true, 204: true, 302: true, 399: true, 400: false, 404: false, 503: false} {
+		if got := StatusOK(status); got != want {
+			t.Errorf("StatusOK(%d) = %v, attendu %v", status, got, want)
+		}
+	}
+}
+
+func TestCheckHTTP(t *testing.T) {
+	plain := statusServer(t, false)
+	secure := statusServer(t, true)
+
+	tests := []struct {
+		name    string
+		target  string
+		wantErr string
+	}{
+		{"200", plain.URL + "/200", ""},
+		{"redirection", plain.URL + "/302", ""},
+		{"503", plain.URL + "/503", "a répondu 503"},
+		{"certificat auto-signé", secure.URL + "/200", ""},
+		{"port fermé", "http://" + closedAddress(t), "injoignable"},
+	}
+	for _, tt := range tests {
+		err := CheckHTTP(context.Background(), tt.target)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s : erreur inattendue %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s : erreur = %v, attendu %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckTCP(t *testing.T) {
+	server := statusServer(t, false)
+	if err := CheckTCP(context.Background(), server.Listener.Addr().String()); err != nil {
+		t.Errorf("port ouvert : %v", err)
+	}
+	if err := CheckTCP(context.Background(), closedAddress(t)); err == nil || !strings.Contains(err.Error(), "injoignable") {
+		t.Errorf("port fermé : erreur = %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Result est le résultat d'une sonde
+type Result struct {
+	Kind    Kind
+	Target  string // URL, adresse ou commande sondée
+	OK      bool
+	Status  int  // statut HTTP (0 si pas de réponse HTTP)
+	TLS     bool // le port parle TLS (https)
+	Latency time.Duration
+	Err     error
+}
+
+// String résume le résultat (ex: "200 OK · 12ms · TLS")
+func (r Result) String() string {
+	latency := r.Latency.Round(time.Millisecond).String()
+	if r.Latency < time.Millisecond {
+		latency = "<1ms"
+	}
+	switch {
+	case r.Err != nil:
+		return "échec : " + r.Err.Error()
+	case r.Kind == KindHTTP:
+		text := fmt.Sprintf("%d %s · %s", r.Status, http.StatusText(r.Status), latency)
+		if r.TLS {
+			text += " · TLS"
+		}
+		return text
+	case r.Kind == KindTCP:
+		return fmt.Sprintf("port ouvert · %s", latency)
+	default:
+		return fmt.Sprintf("commande réussie · %s", latency)
+	}
+}
+
+// ProbeHTTP interroge une URL et mesure la latence de la réponse.
+// Le port est d'abord testé en TLS : une URL http:// servie en https est
+// détectée et interrogée en https. Sans réponse HTTP, la sonde échoue ;
+// l'erreur précise si le port accepte tout de même les connexions.
+func ProbeHTTP(ctx context.Context, target string) Result {
+	result := Result{Kind: KindHTTP, Target: target}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		port := "80"
+		if parsed.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(parsed.Hostname(), port)
+	}
+
+	result.TLS = speaksTLS(ctx, address, parsed.Hostname())
+	if result.TLS {
+		parsed.Scheme = "https"
+	} else {
+		parsed.Scheme = "http"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		// Pas de HTTP : le port est-il au moins ouvert ?
+		if CheckTCP(ctx, address) == nil {
+			result.Err = fmt.Errorf("pas de réponse HTTP sur %s (port ouvert)", parsed.Host)
+			return result
+		}
+		result.Err = fmt.Errorf("%s injoignable: %w", parsed.Host, err)
+		return result
+	}
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	result.OK = StatusOK(resp.StatusCode)
+	return result
+}
+
+// ProbeTCP mesure le temps d'établissement d'une connexion TCP
+func ProbeTCP(ctx context.Context, address string) Result {
+	result := Result{Kind: KindTCP, Target: address}
+	start := time.Now()
+	err := CheckTCP(ctx, address)
+	result.Latency = time.Since(start)
+	result.Err = err
+	result.OK = err == nil
+	return result
+}
+
+// speaksTLS tente une poignée de main TLS sur l'adresse
+func speaksTLS(ctx context.Context, address string, serverName string) bool {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: DefaultTimeout},
+		Config:    &tls.Config{InsecureSkipVerify: true, ServerName: serverName},
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// silentListener accepte les connexions TCP et les ferme sans répondre
+func silentListener(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("écoute locale impossible: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestProbeHTTP(t *testing.T) {
+	plain := statusServer(t, false)
+	secure := statusServer(t, true)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantOK     bool
+		wantStatus int
+		wantTLS    bool
+		wantErr    string
+	}{
+		{"http", plain.URL + "/200", true, 200, false, ""},
+		{"statut d'erreur", plain.URL + "/503", false, 503, false, ""},
+		// URL http:// servie en https : détectée par la poignée de main TLS
+		{"tls détecté", strings.Replace(secure.URL, "https://", "http://", 1) + "/200", true, 200, true, ""},
+		{"https", secure.URL + "/200", true, 200, true, ""},
+		{"port ouvert sans HTTP", "http://" + silentListener(t), false, 0, false, "port ouvert"},
+		{"port fermé", "http://" + closedAddress(t), false, 0, false, "injoignable"},
+	}
+	for _, tt := range tests {
+		result := ProbeHTTP(context.Background(), tt.target)
+		if result.Kind != KindHTTP || result.Target != tt.target {
+			t.Errorf("%s : résultat = %+v", tt.name, result)
+		}
+		if result.OK != tt.wantOK || result.Status != tt.wantStatus || result.TLS != tt.wantTLS {
+			t.Errorf("%s : OK=%v statut=%d TLS=%v, attendu %v %d %v (erreur %v)",
+				tt.name, result.OK, result.Status, result.TLS, tt.wantOK, tt.wantStatus, tt.wantTLS, result.Err)
+		}
+		switch {
+		case tt.wantErr == "" && result.Err != nil:
+			t.Errorf("%s : erreur inattendue %v", tt.name, result.Err)
+		case tt.wantErr != "" && (result.Err == nil || !strings.Contains(result.Err.Error(), tt.wantErr)):
+			t.Errorf("%s : erreur = %v, attendu %q", tt.name, result.Err, tt.wantErr)
+		}
+	}
+
+	if got := ProbeHTTP(context.Background(), secure.URL+"/200").String(); !strings.HasPrefix(got, "200 OK · ") || !strings.HasSuffix(got, " · TLS") {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	address := silentListener(t)
+	if result := ProbeTCP(context.Background(), address); !result.OK || result.Kind != KindTCP || !strings.HasPrefix(result.String(), "port ouvert") {
+		t.Errorf("port ouvert : %+v", result)
+	}
+	if result := ProbeTCP(context.Background(), closedAddress(t)); result.OK || result.Err == nil {
+		t.Errorf("port fermé : %+v", result)
+	}
+}
@@ -4,26 +4,144 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Service représente un service docker d'un projet
 type Service struct {
 	Name      string
-	Status    string // running, exited, created
+	Status    string // running, exited, restarting, paused, created (vide si non créé)
+	Health    string // healthy, unhealthy, starting (vide sans healthcheck)
+	ExitCode  int
+	StartedAt time.Time
 	Container string
 	Ports     string
 }
 
+// Healthy indique si le service tourne et que son healthcheck (s'il existe) passe
+func (s Service) Healthy() bool {
+	return s.Status == "running" && (s.Health == "" || s.Health == "healthy")
+}
+
+// Uptime retourne la durée depuis le démarrage du container
+func (s Service) Uptime() time.Duration {
+	if s.Status != "running" || s.StartedAt.IsZero() {
+		return 0
+	}
+	return time.Since(s.StartedAt).Truncate(time.Second)
+}
+
+// StateString retourne l'état lisible du service (ex: "exited (137)")
+func (s Service) StateString() string {
+	switch {
+	case s.Status == "":
+		return "non créé"
+	case s.Status == "exited" || s.Status == "dead":
+		return fmt.Sprintf("%s (%d)", s.Status, s.ExitCode)
+	case s.Status == "running" && s.Health != "":
+		return s.Health
+	default:
+		return s.Status
+	}
+}
+
+// URLSettings règle la construction des URLs d'un service (vide = déduit du port)
+type URLSettings struct {
+	Scheme   string // http, https, ou tcp (ports listés en hôte:port)
+	Path     string
+	Hostname string
+}
+
+// DefaultComposeFile est le fichier compose principal d'un projet
+const DefaultComposeFile = "docker-compose.yml"
+
 // Project représente un projet Docker complet
 type Project struct {
 	Name         string
 	Path         string
 	ComposePath  string
+	ComposeFiles []string // fichiers passés à compose (-f), relatifs à Path
+	Profiles     []string // profils compose actifs (--profile)
+	EnvFiles     []string // fichiers passés à compose (--env-file), relatifs à Path
+	Env          map[string]string
+	URLSettings  map[string]URLSettings // par service, depuis projects.yml
+	URL          string                 // URL principale configurée (open)
 	Services     []Service
 	Running      bool
 	ServiceCount int
 }
 
+// Files retourne les fichiers compose du projet, dans l'ordre d'application
+func (p *Project) Files() []string {
+	if len(p.ComposeFiles) == 0 {
+		return []string{DefaultComposeFile}
+	}
+	return p.ComposeFiles
+}
+
+// EnvList retourne les variables d'environnement du projet (KEY=VALUE), triées
+func (p *Project) EnvList() []string {
+	keys := make([]string, 0, len(p.Env))
+	for key := range p.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, key+"="+p.Env[key])
+	}
+	return env
+}
+
+// EnvSources décrit les sources d'environnement appliquées aux commandes compose
+func (p *Project) EnvSources() []string {
+	var sources []string
+	if len(p.EnvFiles) == 0 {
+		// Sans --env-file, compose lit le .env du dossier du projet
+		if _, err := os.Stat(filepath.Join(p.Path, ".env")); err == nil {
+			sources = append(sources, ".env (par défaut)")
+		}
+	}
+	for _, file := range p.EnvFiles {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(p.Path, file)
+		}
+		if _, err := os.Stat(path); err != nil {
+			sources = append(sources, file+" (manquant)")
+			continue
+		}
+		sources = append(sources, file)
+	}
+	if len(p.Env) > 0 {
+		keys := make([]string, 0, len(p.Env))
+		for key := range p.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sources = append(sources, "config: "+strings.Join(keys, ", "))
+	}
+	return sources
+}
+
+// MissingFiles retourne les fichiers compose introuvables
+func (p *Project) MissingFiles() []string {
+	var missing []string
+	for _, file := range p.Files() {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(p.Path, file)
+		}
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, file)
+		}
+	}
+	return missing
+}
+
 // GetAbsolutePath retourne le chemin absolu du projet
 func (p *Project) GetAbsolutePath() (string, error) {
 	absPath, err := filepath.Abs(p.Path)
@@ -45,9 +163,35 @@ func (p *Project) DockerComposeExists() bool {
 	return err == nil && !info.IsDir()
 }
 
+// HealthSummary résume l'état des services (ex: "2/3 healthy, worker exited (137)")
+func (p *Project) HealthSummary() string {
+	if len(p.Services) == 0 {
+		return ""
+	}
+
+	healthy := 0
+	var problems []string
+	for _, service := range p.Services {
+		if service.Healthy() {
+			healthy++
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s %s", service.Name, service.StateString()))
+	}
+
+	summary := fmt.Sprintf("%d/%d healthy", healthy, len(p.Services))
+	if len(problems) > 0 {
+		summary += ", " + strings.Join(problems, ", ")
+	}
+	return summary
+}
+
 // StatusString retourne un string formaté du statut
 func (p *Project) StatusString() string {
 	if p.Running {
+		if summary := p.HealthSummary(); summary != "" {
+			return fmt.Sprintf("▶ Running (%s)", summary)
+		}
 		return fmt.Sprintf("▶ Running (%d services)", p.ServiceCount)
 	}
 	return "⏹ Stopped"
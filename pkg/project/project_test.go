@@ -0,0 +1,88 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServiceStateString(t *testing.T) {
+	tests := []struct {
+		service Service
+		want    string
+	}{
+		{Service{}, "non créé"},
+		{Service{Status: "exited", ExitCode: 137}, "exited (137)"},
+		{Service{Status: "dead", ExitCode: 1}, "dead (1)"},
+		{Service{Status: "running", Health: "starting"}, "starting"},
+		{Service{Status: "running"}, "running"},
+		{Service{Status: "paused"}, "paused"},
+	}
+	for _, tt := range tests {
+		if got := tt.service.StateString(); got != tt.want {
+			t.Errorf("%+v : %q, attendu %q", tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestServiceUptime(t *testing.T) {
+	started := Service{Status: "running", StartedAt: time.Now().Add(-90 * time.Second)}
+	if uptime := started.Uptime(); uptime < 90*time.Second || uptime > 92*time.Second {
+		t.Errorf("uptime = %s, attendu ~1m30s", uptime)
+	}
+	if uptime := (Service{Status: "exited", StartedAt: time.Now()}).Uptime(); uptime != 0 {
+		t.Errorf("uptime d'un service arrêté = %s", uptime)
+	}
+}
+
+func TestHealthSummary(t *testing.T) {
+	p := Project{Services: []Service{
+		{Name: "app", Status: "running", Health: "healthy"},
+		{Name: "db", Status: "running"},
+		{Name: "worker", Status: "exited", ExitCode: 137},
+	}}
+	if got, want := p.HealthSummary(), "2/3 healthy, worker exited (137)"; got != want {
+		t.Errorf("résumé = %q, attendu %q", got, want)
+	}
+	if got := (&Project{}).HealthSummary(); got != "" {
+		t.Errorf("résumé sans service = %q", got)
+	}
+}
+
+func TestEnvList(t *testing.T) {
+	p := &Project{Env: map[string]string{"TAG": "1.2", "APP_ENV": "test", "EMPTY": ""}}
+	want := []string{"APP_ENV=test", "EMPTY=", "TAG=1.2"}
+	if got := p.EnvList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvList = %q, attendu %q", got, want)
+	}
+	if got := (&Project{}).EnvList(); len(got) != 0 {
+		t.Errorf("EnvList sans variable = %q", got)
+	}
+}
+
+func TestEnvSources(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", ".env.local"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("A=1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		project Project
+		want    []string
+	}{
+		{"défaut", Project{Path: dir}, []string{".env (par défaut)"}},
+		{"env files", Project{Path: dir, EnvFiles: []string{".env.local", ".env.prod"}}, []string{".env.local", ".env.prod (manquant)"}},
+		{"chemin absolu", Project{Path: t.TempDir(), EnvFiles: []string{filepath.Join(dir, ".env")}}, []string{filepath.Join(dir, ".env")}},
+		{"config", Project{Path: t.TempDir(), Env: map[string]string{"TAG": "1", "APP_ENV": "test"}}, []string{"config: APP_ENV, TAG"}},
+	}
+	for _, tt := range tests {
+		if got := tt.project.EnvSources(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s : %q, attendu %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/phil/docker-manager/pkg/config"
 	"github.com/phil/docker-manager/pkg/docker"
 	"github.com/phil/docker-manager/pkg/project"
 )
@@ -19,29 +22,270 @@ type Model struct {
 	height    int
 	loading   bool
 	lastError string
+	cancel    context.CancelFunc
+	output    chan string
+	lastLine  string
+	events    <-chan docker.Event
+
+	// Mode de démarrage appliqué par [S]tart (index dans startModes)
+	startMode int
+
+	// Sélection des profils compose du projet sélectionné
+	profileMode    bool
+	profileOptions []string
+	profileCursor  int
+
+	// Dernières sondes par projet (touche c)
+	probes map[string][]docker.ServiceProbe
+
+	// Sélection d'une tâche du projet sélectionné
+	taskMode    bool
+	taskOptions []docker.Task
+	taskCursor  int
+}
+
+// eventMsg transporte un événement Docker reçu de l'EventHub
+type eventMsg docker.Event
+
+// refreshedMsg contient le statut à jour d'un projet
+type refreshedMsg struct {
+	index   int
+	project project.Project
+}
+
+// profilesLoadedMsg contient les profils déclarés par un projet
+type profilesLoadedMsg struct {
+	index    int
+	profiles []string
+	err      error
+}
+
+// actionDoneMsg est envoyé quand une action Docker lancée en arrière-plan se termine
+type actionDoneMsg struct {
+	index   int
+	project project.Project
+	message string
+	err     error
+}
+
+// startMode est un mode de démarrage proposé par le dashboard
+type startMode struct {
+	name  string
+	apply func(opts *docker.StartOptions)
+}
+
+// startModes sont appliqués par-dessus le mode par défaut du projet (projects.yml)
+var startModes = []startMode{
+	{name: "défaut", apply: func(opts *docker.StartOptions) {}},
+	{name: "sans build", apply: func(opts *docker.StartOptions) { opts.NoBuild = true }},
+	{name: "pull", apply: func(opts *docker.StartOptions) { opts.Pull = true }},
+	{name: "build sans cache", apply: func(opts *docker.StartOptions) { opts.NoBuild, opts.NoCache = false, true }},
+	{name: "recréation forcée", apply: func(opts *docker.StartOptions) { opts.ForceRecreate = true }},
+	{name: "attente santé", apply: func(opts *docker.StartOptions) { opts.Wait = true }},
 }
 
-// NewModel crée un nouveau modèle de dashboard
-func NewModel(projects []project.Project, manager *docker.Manager) *Model {
+// startOptions retourne les options de démarrage du projet pour le mode courant
+func (m *Model) startOptions(projectName string) docker.StartOptions {
+	var opts docker.StartOptions
+	if cfg, err := config.LoadConfig(); err == nil {
+		projectCfg := cfg.GetProjectConfig(projectName)
+		opts = docker.StartOptionsFromConfig(projectCfg.Start)
+		opts.HealthChecks = docker.HealthChecksFromConfig(projectCfg.Services)
+	}
+	startModes[m.startMode].apply(&opts)
+	return opts
+}
+
+// NewModel crée un nouveau modèle de dashboard.
+// La sortie du manager est redirigée vers le dashboard pour ne pas
+// corrompre l'écran Bubble Tea. Si events est fourni, les statuts sont
+// mis à jour en direct à chaque événement Docker.
+func NewModel(projects []project.Project, manager *docker.Manager, events <-chan docker.Event) *Model {
+	output := make(chan string, 100)
+	writer := newLineWriter(output)
+	manager.Stdin = nil
+	manager.Stdout = writer
+	manager.Stderr = writer
+	manager.Progress = func(event docker.ProgressEvent) {
+		select {
+		case output <- event.Message:
+		default:
+		}
+	}
+
 	return &Model{
 		projects: projects,
 		selected: 0,
 		manager:  manager,
 		message:  "Bienvenue dans Docker Manager",
+		output:   output,
+		events:   events,
+		probes:   make(map[string][]docker.ServiceProbe),
 	}
 }
 
 // Init initialise le modèle
 func (m Model) Init() tea.Cmd {
-	return nil
+	if m.events != nil {
+		return tea.Batch(waitForOutput(m.output), waitForEvent(m.events))
+	}
+	return waitForOutput(m.output)
+}
+
+// waitForEvent attend le prochain événement Docker
+func waitForEvent(events <-chan docker.Event) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return eventMsg(event)
+	}
+}
+
+// refreshProject recharge le statut et l'état des services d'un projet
+func refreshProject(manager *docker.Manager, p project.Project) project.Project {
+	ctx := context.Background()
+	p.Running, p.ServiceCount, _ = manager.GetStatus(ctx, &p)
+	p.Services = nil
+	if p.Running {
+		if services, err := manager.GetServiceStatuses(ctx, &p); err == nil {
+			p.Services = services
+		}
+	}
+	return p
+}
+
+// runAction exécute une action sur le projet sélectionné sans bloquer l'interface.
+// Le contexte est annulé par Ctrl-C pendant l'action.
+func (m *Model) runAction(success string, action func(ctx context.Context, p *project.Project) error) tea.Cmd {
+	if m.loading || m.selected >= len(m.projects) {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.loading = true
+	m.lastError = ""
+	m.lastLine = ""
+
+	index := m.selected
+	p := m.projects[index]
+	manager := m.manager
+	m.message = fmt.Sprintf("⏳ %s en cours...", p.Name)
+
+	return func() tea.Msg {
+		defer cancel()
+		err := action(ctx, &p)
+
+		// Rafraîchir le statut du projet après l'action
+		p = refreshProject(manager, p)
+
+		return actionDoneMsg{
+			index:   index,
+			project: p,
+			message: fmt.Sprintf(success, p.Name),
+			err:     err,
+		}
+	}
+}
+
+// loadProfiles récupère les profils disponibles du projet sélectionné
+func (m *Model) loadProfiles() tea.Cmd {
+	if m.loading || m.selected >= len(m.projects) {
+		return nil
+	}
+
+	index := m.selected
+	p := m.projects[index]
+	manager := m.manager
+	return func() tea.Msg {
+		profiles, err := manager.GetProfiles(context.Background(), &p)
+		return profilesLoadedMsg{index: index, profiles: profiles, err: err}
+	}
+}
+
+// updateProfileMode gère les touches pendant la sélection des profils
+func (m Model) updateProfileMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.profileCursor > 0 {
+			m.profileCursor--
+		}
+	case "down", "j":
+		if m.profileCursor < len(m.profileOptions)-1 {
+			m.profileCursor++
+		}
+	case " ", "x":
+		p := &m.projects[m.selected]
+		p.Profiles = toggleProfile(p.Profiles, m.profileOptions[m.profileCursor])
+	case "enter", "esc", "p", "q":
+		m.profileMode = false
+		p := m.projects[m.selected]
+		if len(p.Profiles) > 0 {
+			m.message = fmt.Sprintf("Profils de %s : %s", p.Name, strings.Join(p.Profiles, ", "))
+		} else {
+			m.message = fmt.Sprintf("Aucun profil actif pour %s", p.Name)
+		}
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// toggleProfile active ou désactive un profil
+func toggleProfile(profiles []string, profile string) []string {
+	toggled := make([]string, 0, len(profiles)+1)
+	found := false
+	for _, existing := range profiles {
+		if existing == profile {
+			found = true
+			continue
+		}
+		toggled = append(toggled, existing)
+	}
+	if !found {
+		toggled = append(toggled, profile)
+	}
+	return toggled
+}
+
+// applyStatus reporte le statut rafraîchi sans écraser les réglages
+// faits entre-temps (profils sélectionnés par exemple)
+func (m *Model) applyStatus(index int, refreshed project.Project) {
+	if index >= len(m.projects) || m.projects[index].Name != refreshed.Name {
+		return
+	}
+	m.projects[index].Running = refreshed.Running
+	m.projects[index].ServiceCount = refreshed.ServiceCount
+	m.projects[index].Services = refreshed.Services
 }
 
 // Update gère les mises à jour du modèle
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.profileMode {
+			return m.updateProfileMode(msg)
+		}
+		if m.taskMode {
+			return m.updateTaskMode(msg)
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "ctrl+c":
+			// Pendant une action, Ctrl-C annule l'action au lieu de quitter
+			if m.loading && m.cancel != nil {
+				m.cancel()
+				m.message = "⏹ Annulation en cours..."
+				return m, nil
+			}
+			return m, tea.Quit
+
+		case "q":
+			if m.cancel != nil {
+				m.cancel()
+			}
 			return m, tea.Quit
 
 		case "up", "k":
@@ -54,42 +298,131 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "s":
-			if m.selected < len(m.projects) {
-				p := m.projects[m.selected]
-				m.loading = true
-				if err := m.manager.StartProject(&p); err != nil {
-					m.lastError = err.Error()
-				} else {
-					m.message = fmt.Sprintf("✅ Projet %s démarré", p.Name)
-				}
-				m.loading = false
+			if m.selected >= len(m.projects) {
+				return m, nil
 			}
+			manager := m.manager
+			opts := m.startOptions(m.projects[m.selected].Name)
+			return m, m.runAction("✅ Projet %s démarré ("+opts.Describe()+")", func(ctx context.Context, p *project.Project) error {
+				return manager.StartProject(ctx, p, opts)
+			})
+
+		case "m":
+			m.startMode = (m.startMode + 1) % len(startModes)
+			m.message = "Mode de démarrage : " + startModes[m.startMode].name
 
 		case "d":
-			if m.selected < len(m.projects) {
-				p := m.projects[m.selected]
-				m.loading = true
-				if err := m.manager.StopProject(&p); err != nil {
-					m.lastError = err.Error()
-				} else {
-					m.message = fmt.Sprintf("✅ Projet %s arrêté", p.Name)
-				}
-				m.loading = false
-			}
+			manager := m.manager
+			return m, m.runAction("✅ Projet %s arrêté", func(ctx context.Context, p *project.Project) error {
+				return manager.StopProject(ctx, p, docker.StopOptions{Remove: true})
+			})
+
+		case "p":
+			return m, m.loadProfiles()
+
+		case "t":
+			m.loadTasks()
+
+		case "c":
+			return m, m.probeProject()
+
+		case "o":
+			return m, m.openProject()
 
 		case "r":
-			if m.selected < len(m.projects) {
-				p := m.projects[m.selected]
-				m.loading = true
-				if err := m.manager.RestartService(&p, ""); err != nil {
-					m.lastError = err.Error()
-				} else {
-					m.message = fmt.Sprintf("✅ Projet %s redémarré", p.Name)
-				}
-				m.loading = false
+			if m.selected >= len(m.projects) {
+				return m, nil
+			}
+			manager := m.manager
+			// Attente de la santé selon start.wait de projects.yml ou le mode courant
+			defaults := m.startOptions(m.projects[m.selected].Name)
+			restartOpts := docker.RestartOptions{
+				Wait:         defaults.Wait,
+				WaitTimeout:  defaults.WaitTimeout,
+				HealthChecks: defaults.HealthChecks,
+			}
+			return m, m.runAction("✅ Projet %s redémarré", func(ctx context.Context, p *project.Project) error {
+				return manager.RestartProject(ctx, p, restartOpts)
+			})
+		}
+
+	case eventMsg:
+		event := docker.Event(msg)
+		cmds := []tea.Cmd{waitForEvent(m.events)}
+		for i := range m.projects {
+			if m.projects[i].Name != event.Project {
+				continue
+			}
+			if !m.loading {
+				m.message = "⚡ " + event.String()
+			}
+			index, p, manager := i, m.projects[i], m.manager
+			cmds = append(cmds, func() tea.Msg {
+				return refreshedMsg{index: index, project: refreshProject(manager, p)}
+			})
+		}
+		return m, tea.Batch(cmds...)
+
+	case profilesLoadedMsg:
+		switch {
+		case msg.err != nil:
+			m.lastError = msg.err.Error()
+		case len(msg.profiles) == 0:
+			m.message = "Aucun profil déclaré dans ce projet"
+		case msg.index == m.selected:
+			m.profileMode = true
+			m.profileOptions = msg.profiles
+			m.profileCursor = 0
+		}
+
+	case taskDoneMsg:
+		if msg.err != nil {
+			m.lastError = msg.err.Error()
+			m.message = ""
+		} else {
+			m.message = fmt.Sprintf("✅ Tâche %s terminée", msg.name)
+		}
+		if msg.index < len(m.projects) {
+			index, p, manager := msg.index, m.projects[msg.index], m.manager
+			return m, func() tea.Msg {
+				return refreshedMsg{index: index, project: refreshProject(manager, p)}
 			}
 		}
 
+	case openedMsg:
+		if msg.err != nil {
+			m.lastError = msg.err.Error()
+			break
+		}
+		m.message = "🌐 " + msg.url + " ouvert"
+
+	case probesMsg:
+		if msg.err != nil {
+			m.lastError = msg.err.Error()
+			break
+		}
+		m.probes[msg.name] = msg.probes
+		m.message = probeSummary(msg.name, msg.probes)
+
+	case refreshedMsg:
+		m.applyStatus(msg.index, msg.project)
+
+	case outputMsg:
+		m.lastLine = string(msg)
+		return m, waitForOutput(m.output)
+
+	case actionDoneMsg:
+		m.loading = false
+		m.lastLine = ""
+		m.cancel = nil
+		m.applyStatus(msg.index, msg.project)
+		if msg.err != nil {
+			m.lastError = msg.err.Error()
+			m.message = ""
+		} else {
+			m.message = msg.message
+		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -132,14 +465,58 @@ func (m Model) View() string {
 	for i, p := range m.projects {
 		status := p.StatusString()
 		line := fmt.Sprintf("  %-20s  %s", p.Name, status)
+		if len(p.Profiles) > 0 {
+			line += fmt.Sprintf("  [%s]", strings.Join(p.Profiles, ","))
+		}
 
 		if i == m.selected {
 			projectLines += selectedStyle.Render(line) + "\n"
+			if probes := m.probeLines(p.Name); probes != "" {
+				projectLines += normalStyle.Render(probes) + "\n"
+			}
 		} else {
 			projectLines += normalStyle.Render(line) + "\n"
 		}
 	}
 
+	// Sélection des profils
+	if m.profileMode && m.selected < len(m.projects) {
+		active := make(map[string]bool)
+		for _, profile := range m.projects[m.selected].Profiles {
+			active[profile] = true
+		}
+
+		projectLines += "\n" + headerStyle.Render("Profils de "+m.projects[m.selected].Name+":") + "\n"
+		for i, profile := range m.profileOptions {
+			check := "[ ]"
+			if active[profile] {
+				check = "[x]"
+			}
+			line := fmt.Sprintf("  %s %s", check, profile)
+			if i == m.profileCursor {
+				projectLines += selectedStyle.Render(line) + "\n"
+			} else {
+				projectLines += normalStyle.Render(line) + "\n"
+			}
+		}
+	}
+
+	// Sélection d'une tâche
+	if m.taskMode && m.selected < len(m.projects) {
+		projectLines += "\n" + headerStyle.Render("Tâches de "+m.projects[m.selected].Name+":") + "\n"
+		for i, task := range m.taskOptions {
+			line := fmt.Sprintf("  %-16s %s", task.Name, task.CommandString())
+			if task.Description != "" {
+				line = fmt.Sprintf("  %-16s %s", task.Name, task.Description)
+			}
+			if i == m.taskCursor {
+				projectLines += selectedStyle.Render(line) + "\n"
+			} else {
+				projectLines += normalStyle.Render(line) + "\n"
+			}
+		}
+	}
+
 	// Message de statut
 	messageStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("10")).
@@ -147,6 +524,18 @@ func (m Model) View() string {
 
 	statusText := messageStyle.Render(m.message)
 
+	// Dernière ligne de sortie de l'action en cours
+	if m.loading && m.lastLine != "" {
+		outputStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")).
+			Margin(0, 0, 0, 1)
+		line := []rune(m.lastLine)
+		if m.width > 4 && len(line) > m.width-4 {
+			line = line[:m.width-4]
+		}
+		statusText += "\n" + outputStyle.Render(string(line))
+	}
+
 	// Erreur si présente
 	errorText := ""
 	if m.lastError != "" {
@@ -161,7 +550,17 @@ func (m Model) View() string {
 		Foreground(lipgloss.Color("8")).
 		Margin(1, 0, 0, 1)
 
-	commands := cmdStyle.Render("[S]tart  [D]rop  [R]estart  [U]p/[D]own  [Q]uit")
+	commandText := fmt.Sprintf("[S]tart  [M]ode: %s  [D]rop  [R]estart  [P]rofils  [T]âches  [C]heck  [O]uvrir  [U]p/[D]own  [Q]uit", startModes[m.startMode].name)
+	if m.profileMode {
+		commandText = "Espace: activer/désactiver  Entrée/Échap: valider"
+	}
+	if m.taskMode {
+		commandText = "Entrée: exécuter la tâche  Échap: annuler"
+	}
+	if m.loading {
+		commandText = "Ctrl-C: annuler l'action en cours"
+	}
+	commands := cmdStyle.Render(commandText)
 
 	return fmt.Sprintf("%s\n\n%s%s\n%s%s\n%s\n", title, headerStyle.Render("Projects:"), projectLines, statusText, errorText, commands)
 }
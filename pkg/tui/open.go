@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/phil/docker-manager/pkg/browser"
+	"github.com/phil/docker-manager/pkg/config"
+)
+
+// openedMsg indique le résultat de l'ouverture de l'URL d'un projet
+type openedMsg struct {
+	url string
+	err error
+}
+
+// openProject ouvre l'URL principale du projet sélectionné dans le navigateur
+func (m *Model) openProject() tea.Cmd {
+	if m.selected >= len(m.projects) {
+		return nil
+	}
+
+	p := m.projects[m.selected]
+	if !p.Running && p.URL == "" {
+		m.message = fmt.Sprintf("%s n'est pas démarré", p.Name)
+		return nil
+	}
+
+	opener := ""
+	if cfg, err := config.LoadConfig(); err == nil {
+		opener = cfg.Opener
+	}
+
+	m.message = fmt.Sprintf("🌐 Ouverture de %s...", p.Name)
+	manager := m.manager
+	return func() tea.Msg {
+		target, err := manager.PrimaryURL(context.Background(), &p, "")
+		if err == nil {
+			err = browser.Open(opener, target)
+		}
+		return openedMsg{url: target, err: err}
+	}
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// outputMsg transporte une ligne de sortie Docker vers le dashboard
+type outputMsg string
+
+// lineWriter découpe la sortie des commandes en lignes et les envoie au dashboard.
+// Les retours chariot (barres de progression de compose) sont traités comme des fins de ligne.
+type lineWriter struct {
+	mu      sync.Mutex
+	partial strings.Builder
+	lines   chan<- string
+}
+
+func newLineWriter(lines chan<- string) *lineWriter {
+	return &lineWriter{lines: lines}
+}
+
+// Write implémente io.Writer
+func (w *lineWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, b := range data {
+		if b != '\n' && b != '\r' {
+			w.partial.WriteByte(b)
+			continue
+		}
+		w.flush()
+	}
+	return len(data), nil
+}
+
+// flush envoie la ligne en cours sans jamais bloquer la commande Docker
+func (w *lineWriter) flush() {
+	line := strings.TrimSpace(w.partial.String())
+	w.partial.Reset()
+	if line == "" {
+		return
+	}
+	select {
+	case w.lines <- line:
+	default:
+	}
+}
+
+// waitForOutput attend la prochaine ligne de sortie
+func waitForOutput(lines <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		return outputMsg(<-lines)
+	}
+}
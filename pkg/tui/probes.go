@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/phil/docker-manager/pkg/config"
+	"github.com/phil/docker-manager/pkg/docker"
+)
+
+// probesMsg contient le résultat des sondes d'un projet
+type probesMsg struct {
+	name   string
+	probes []docker.ServiceProbe
+	err    error
+}
+
+// probeProject sonde les URLs et health_check du projet sélectionné
+func (m *Model) probeProject() tea.Cmd {
+	if m.selected >= len(m.projects) {
+		return nil
+	}
+
+	p := m.projects[m.selected]
+	if !p.Running {
+		m.message = fmt.Sprintf("%s n'est pas démarré", p.Name)
+		return nil
+	}
+
+	var checks map[string]string
+	if cfg, err := config.LoadConfig(); err == nil {
+		checks = docker.HealthChecksFromConfig(cfg.GetProjectConfig(p.Name).Services)
+	}
+
+	m.message = fmt.Sprintf("🩺 Sondes de %s en cours...", p.Name)
+	manager := m.manager
+	return func() tea.Msg {
+		probes, err := manager.ProbeServices(context.Background(), &p, checks)
+		return probesMsg{name: p.Name, probes: probes, err: err}
+	}
+}
+
+// probeSummary résume les sondes (ex: "🩺 pbwww : 3/4 OK")
+func probeSummary(name string, probes []docker.ServiceProbe) string {
+	ok := 0
+	for _, probe := range probes {
+		if probe.OK {
+			ok++
+		}
+	}
+	if len(probes) == 0 {
+		return fmt.Sprintf("🩺 %s : aucun port publié ni health_check", name)
+	}
+	return fmt.Sprintf("🩺 %s : %d/%d OK", name, ok, len(probes))
+}
+
+// probeLines affiche les sondes du projet sélectionné
+func (m Model) probeLines(name string) string {
+	probes, ok := m.probes[name]
+	if !ok {
+		return ""
+	}
+
+	lines := make([]string, 0, len(probes))
+	for _, probe := range probes {
+		icon := "✅"
+		if !probe.OK {
+			icon = "❌"
+		}
+		lines = append(lines, fmt.Sprintf("    %s %-12s %-28s %s", icon, probe.Service, probe.Target, probe.Result))
+	}
+	return strings.Join(lines, "\n")
+}
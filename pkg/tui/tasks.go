@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/phil/docker-manager/pkg/config"
+	"github.com/phil/docker-manager/pkg/docker"
+	"github.com/phil/docker-manager/pkg/project"
+)
+
+// taskDoneMsg est envoyé quand une tâche lancée depuis le dashboard se termine
+type taskDoneMsg struct {
+	index int
+	name  string
+	err   error
+}
+
+// taskCommand exécute une tâche au premier plan (tea.Exec) : le dashboard
+// rend le terminal le temps de la tâche, qui peut donc être interactive
+type taskCommand struct {
+	manager docker.Manager
+	project project.Project
+	task    docker.Task
+}
+
+func (c *taskCommand) SetStdin(r io.Reader)  { c.manager.Stdin = r }
+func (c *taskCommand) SetStdout(w io.Writer) { c.manager.Stdout = w }
+func (c *taskCommand) SetStderr(w io.Writer) { c.manager.Stderr = w }
+
+// Run exécute la tâche puis attend Entrée avant de revenir au dashboard
+func (c *taskCommand) Run() error {
+	c.manager.Progress = nil
+	err := c.manager.RunTask(context.Background(), &c.project, c.task, nil, docker.ExecOptions{TTY: true})
+	if err != nil {
+		fmt.Fprintf(c.manager.Stdout, "❌ %v\n", err)
+	}
+
+	fmt.Fprint(c.manager.Stdout, "\nEntrée pour revenir au dashboard...")
+	bufio.NewReader(c.manager.Stdin).ReadString('\n')
+	return err
+}
+
+// loadTasks affiche les tâches du projet sélectionné (projects.yml)
+func (m *Model) loadTasks() {
+	if m.loading || m.selected >= len(m.projects) {
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		m.lastError = err.Error()
+		return
+	}
+
+	tasks := docker.TasksFromConfig(cfg.GetProjectConfig(m.projects[m.selected].Name))
+	if len(tasks) == 0 {
+		m.message = "Aucune tâche définie pour ce projet (tasks: dans projects.yml)"
+		return
+	}
+	m.taskMode = true
+	m.taskOptions = tasks
+	m.taskCursor = 0
+}
+
+// updateTaskMode gère les touches pendant la sélection d'une tâche
+func (m Model) updateTaskMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.taskCursor > 0 {
+			m.taskCursor--
+		}
+	case "down", "j":
+		if m.taskCursor < len(m.taskOptions)-1 {
+			m.taskCursor++
+		}
+	case "enter":
+		m.taskMode = false
+		index := m.selected
+		task := m.taskOptions[m.taskCursor]
+		m.message = fmt.Sprintf("▶️  Tâche %s en cours...", task.Name)
+		m.lastError = ""
+
+		// Copie du manager : les flux du dashboard ne sont pas modifiés
+		command := &taskCommand{manager: *m.manager, project: m.projects[index], task: task}
+		return m, tea.Exec(command, func(err error) tea.Msg {
+			return taskDoneMsg{index: index, name: task.Name, err: err}
+		})
+	case "esc", "t", "q":
+		m.taskMode = false
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}